@@ -3,12 +3,12 @@ package cmd
 import (
 	"bytes"
 	"errors"
+	"html"
 	"net/url"
 	"os"
 
 	ado "github.com/namoshek/kustomize-diff/azuredevops"
 	k8s "github.com/namoshek/kustomize-diff/kubernetes"
-	kustomize "github.com/namoshek/kustomize-diff/kustomize"
 	utils "github.com/namoshek/kustomize-diff/utils"
 
 	"github.com/spf13/cobra"
@@ -19,6 +19,7 @@ import (
 type AzureDevOpsCommandFlags struct {
 	CommentPerResource   bool
 	HideDiffInSpoiler    bool
+	IncludeSummary       bool
 	PrependedCommentText string
 	AppendedCommentText  string
 }
@@ -46,6 +47,7 @@ func init() {
 	azuredevopsCmd.Flags().IntP("pull-request-id", "u", 0, "The id of the pull request that should be decorated")
 	azuredevopsCmd.Flags().Bool("comment-per-resource", false, "Create a separate comment for each resource with differences")
 	azuredevopsCmd.Flags().Bool("hide-diff-in-spoiler", false, "Add a spoiler around diffs to prevent displaying large comments")
+	azuredevopsCmd.Flags().Bool("include-summary", false, "Add a table of the changed resources before the diffs; with --comment-per-resource, the table is posted as separate comment")
 	azuredevopsCmd.Flags().String("prepended-comment-text", "", "Text to prepend to created pull request comments; it is added before and outside spoilers if enabled")
 	azuredevopsCmd.Flags().String("appended-comment-text", "", "Text to append to created pull request comments; it is added after and outside spoilers if enabled")
 }
@@ -58,46 +60,61 @@ func runAzuredevopsCommand(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Attempt to create the Kustomizations of the provided directories.
-	pathToOldVersion, pathToNewVersion := args[0], args[1]
-
-	kustomizeExecutable, err := cmd.Flags().GetString("kustomize-executable")
+	diffOptions, err := parseDiffOptions(cmd)
 	if err != nil {
-		utils.Logger.Error("Reading --kustomize-executable option failed.")
+		utils.Logger.Error("Flag validation failed.", zap.Error(err))
 		os.Exit(1)
 	}
 
-	oldKustomization, newKustomization, err := kustomize.BuildKustomizations(kustomizeExecutable, pathToOldVersion, pathToNewVersion)
+	outputOptions, err := parseSharedOutputOptions(cmd)
 	if err != nil {
-		utils.Logger.Error("Building Kustomizations failed.", zap.Error(err))
+		utils.Logger.Error("Flag validation failed.", zap.Error(err))
 		os.Exit(1)
 	}
 
-	// Create a diff of both Kustomizations.
-	diffs, err := k8s.CreateDiffForManifestFiles(oldKustomization, newKustomization)
+	// Build the Kustomizations of the provided directories and create a diff of both.
+	pathToOldVersion, pathToNewVersion := args[0], args[1]
+
+	report, err := buildAndDiffKustomizations(cmd, diffOptions, pathToOldVersion, pathToNewVersion)
 	if err != nil {
 		utils.Logger.Error("Creating the diff failed.", zap.Error(err))
 		os.Exit(1)
 	}
 
-	if len(diffs) == 0 {
+	// Resources which only differ in default values and filtered out resources are reported even without diffs.
+	if len(report.Diffs) == 0 && len(report.DefaultsOnly) == 0 && report.FilteredOut == 0 {
 		utils.Logger.Debug("No diff found, exiting.")
 		os.Exit(0)
 	}
 
+	// Post the summary as separate comment, if each resource gets its own comment.
+	if azureDevOpsCommandFlags.IncludeSummary && azureDevOpsCommandFlags.CommentPerResource {
+		err = createPullRequestSummaryComment(report, azureDevOpsParameters, azureDevOpsCommandFlags)
+		if err != nil {
+			utils.Logger.Error("Creating pull request comment for summary failed.", zap.Error(err))
+			os.Exit(1)
+		}
+	}
+
 	// Prepare diff slices to process depending on the command flags.
-	var diffSlices [][]k8s.ManifestDiff
+	// If each resource gets its own comment, the resources which only differ in default values and the number of
+	// filtered out resources are posted as final comment.
+	var diffSlices []*k8s.DiffReport
 	if azureDevOpsCommandFlags.CommentPerResource {
-		for _, diff := range diffs {
-			diffSlices = append(diffSlices, []k8s.ManifestDiff{diff})
+		for _, diff := range report.Diffs {
+			diffSlices = append(diffSlices, &k8s.DiffReport{Diffs: []k8s.ManifestDiff{diff}})
+		}
+
+		if len(report.DefaultsOnly) > 0 || report.FilteredOut > 0 {
+			diffSlices = append(diffSlices, &k8s.DiffReport{DefaultsOnly: report.DefaultsOnly, FilteredOut: report.FilteredOut})
 		}
 	} else {
-		diffSlices = append(diffSlices, diffs)
+		diffSlices = append(diffSlices, report)
 	}
 
 	// Process the diff slices one-by-one.
 	for _, diffSlice := range diffSlices {
-		err = createPullRequestCommentForManifests(diffSlice, azureDevOpsParameters, azureDevOpsCommandFlags)
+		err = createPullRequestCommentForManifests(diffSlice, diffOptions, outputOptions, azureDevOpsParameters, azureDevOpsCommandFlags)
 		if err != nil {
 			utils.Logger.Error("Creating pull request comment for diff slice failed.", zap.Error(err))
 			os.Exit(1)
@@ -107,23 +124,40 @@ func runAzuredevopsCommand(cmd *cobra.Command, args []string) {
 	os.Exit(0)
 }
 
-// Creates a pull request comment with the given diffs.
-func createPullRequestCommentForManifests(diffs []k8s.ManifestDiff, azureDevOpsParameters *ado.AzureDevOpsParameters, azureDevOpsCommandFlags *AzureDevOpsCommandFlags) error {
-	// Iterate the diffs and print them into a buffer.
+// Creates a pull request comment with the diffs of the given report.
+func createPullRequestCommentForManifests(report *k8s.DiffReport, diffOptions *k8s.DiffOptions, outputOptions *outputOptions, azureDevOpsParameters *ado.AzureDevOpsParameters, azureDevOpsCommandFlags *AzureDevOpsCommandFlags) error {
+	// Print the diffs into a buffer.
 	diffBuffer := new(bytes.Buffer)
-	for _, diff := range diffs {
-		k8s.PrintDiff(&diff, true, diffBuffer)
+
+	err := printDiffs(report, diffOptions, outputOptions, diffBuffer)
+	if err != nil {
+		return errors.Join(errors.New("Printing diffs to buffer failed."), err)
 	}
 
+	// Comments without diffs only contain short notes, which are not hidden in a spoiler.
 	diffContent := diffBuffer.String()
-	if azureDevOpsCommandFlags.HideDiffInSpoiler {
-		diffContent = wrapContentInSpoiler(diffContent)
+	if azureDevOpsCommandFlags.HideDiffInSpoiler && len(report.Diffs) > 0 {
+		// A comment for a single resource uses the header as spoiler summary, so the resource is visible without expanding it.
+		summary := "Show Diff"
+		if len(report.Diffs) == 1 && outputOptions.Headers {
+			summary = report.Diffs[0].Header()
+		}
+
+		diffContent = wrapContentInSpoiler(diffContent, summary)
+	}
+
+	// The summary is added before and outside of the spoiler, if all diffs are posted in one comment.
+	if azureDevOpsCommandFlags.IncludeSummary && !azureDevOpsCommandFlags.CommentPerResource {
+		summaryBuffer := new(bytes.Buffer)
+		k8s.PrintSummaryTable(report, true, summaryBuffer)
+
+		diffContent = summaryBuffer.String() + "\n" + diffContent
 	}
 
 	// Use the buffer to create a comment on the pull request.
 	contentBuffer := bytes.NewBufferString("")
 
-	err := writeContentToBuffer(contentBuffer, diffContent, azureDevOpsCommandFlags)
+	err = writeContentToBuffer(contentBuffer, diffContent, azureDevOpsCommandFlags)
 	if err != nil {
 		return errors.Join(errors.New("Writing content to buffer failed."), err)
 	}
@@ -136,6 +170,26 @@ func createPullRequestCommentForManifests(diffs []k8s.ManifestDiff, azureDevOpsP
 	return nil
 }
 
+// Creates a pull request comment with a summary table of the diffs of the given report.
+func createPullRequestSummaryComment(report *k8s.DiffReport, azureDevOpsParameters *ado.AzureDevOpsParameters, azureDevOpsCommandFlags *AzureDevOpsCommandFlags) error {
+	summaryBuffer := new(bytes.Buffer)
+	k8s.PrintSummaryTable(report, true, summaryBuffer)
+
+	contentBuffer := bytes.NewBufferString("")
+
+	err := writeContentToBuffer(contentBuffer, summaryBuffer.String(), azureDevOpsCommandFlags)
+	if err != nil {
+		return errors.Join(errors.New("Writing summary to buffer failed."), err)
+	}
+
+	err = ado.CreatePullRequestComment(azureDevOpsParameters, contentBuffer.String())
+	if err != nil {
+		return errors.Join(errors.New("Creating pull request comment failed."), err)
+	}
+
+	return nil
+}
+
 // Parses and validates the command flags according to our requirements.
 // The flags are only validated structurally and requests may still fail if improper credentials are passed.
 func parseAndValidateFlags(cmd *cobra.Command) (*ado.AzureDevOpsParameters, *AzureDevOpsCommandFlags, error) {
@@ -190,6 +244,11 @@ func parseAndValidateFlags(cmd *cobra.Command) (*ado.AzureDevOpsParameters, *Azu
 		return nil, nil, errors.New("The provided hide-diff-in-spoiler is invalid.")
 	}
 
+	includeSummary, err := cmd.Flags().GetBool("include-summary")
+	if err != nil {
+		return nil, nil, errors.New("The provided include-summary is invalid.")
+	}
+
 	// Ensure optional texts were passed successfully.
 	prependedCommentText, err := cmd.Flags().GetString("prepended-comment-text")
 	if err != nil {
@@ -212,6 +271,7 @@ func parseAndValidateFlags(cmd *cobra.Command) (*ado.AzureDevOpsParameters, *Azu
 		&AzureDevOpsCommandFlags{
 			CommentPerResource:   commentPerResource,
 			HideDiffInSpoiler:    hideDiffInSpoiler,
+			IncludeSummary:       includeSummary,
 			PrependedCommentText: prependedCommentText,
 			AppendedCommentText:  appendedCommentText,
 		},
@@ -241,6 +301,6 @@ func writeContentToBuffer(buffer *bytes.Buffer, content string, azureDevOpsComma
 	return nil
 }
 
-func wrapContentInSpoiler(content string) string {
-	return "<details>\n<summary>Show Diff</summary>\n\n" + content + "\n</details>"
+func wrapContentInSpoiler(content string, summary string) string {
+	return "<details>\n<summary>" + html.EscapeString(summary) + "</summary>\n\n" + content + "\n</details>"
 }
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"errors"
+	"slices"
+
+	k8s "github.com/namoshek/kustomize-diff/kubernetes"
+	kustomize "github.com/namoshek/kustomize-diff/kustomize"
+
+	"github.com/spf13/cobra"
+)
+
+// Builds the Kustomizations of the given directories and creates the diff of both using the given options.
+func buildAndDiffKustomizations(cmd *cobra.Command, diffOptions *k8s.DiffOptions, pathToOldVersion string, pathToNewVersion string) (*k8s.DiffReport, error) {
+	kustomizeExecutable, err := cmd.Flags().GetString("kustomize-executable")
+	if err != nil {
+		return nil, errors.Join(errors.New("Reading --kustomize-executable option failed."), err)
+	}
+
+	oldKustomization, newKustomization, err := kustomize.BuildKustomizations(kustomizeExecutable, pathToOldVersion, pathToNewVersion)
+	if err != nil {
+		return nil, errors.Join(errors.New("Building Kustomizations failed."), err)
+	}
+
+	report, err := k8s.CreateDiffForManifestFiles(oldKustomization, newKustomization, diffOptions)
+	if err != nil {
+		return nil, errors.Join(errors.New("Creating the diff failed."), err)
+	}
+
+	return report, nil
+}
+
+// Parses the diff options from the persistent flags of the root command.
+// Secrets are always redacted; commands may opt out of the redaction afterwards.
+func parseDiffOptions(cmd *cobra.Command) (*k8s.DiffOptions, error) {
+	sort, err := cmd.Flags().GetString("sort")
+	if err != nil {
+		return nil, errors.New("The provided sort is invalid.")
+	}
+
+	sortOrder, err := k8s.ParseSortOrder(sort)
+	if err != nil {
+		return nil, err
+	}
+
+	diffStyleValue, err := cmd.Flags().GetString("diff-style")
+	if err != nil {
+		return nil, errors.New("The provided diff-style is invalid.")
+	}
+
+	diffStyle, err := k8s.ParseDiffStyle(diffStyleValue)
+	if err != nil {
+		return nil, err
+	}
+
+	contextLines, err := cmd.Flags().GetInt("context")
+	if err != nil || contextLines < 0 {
+		return nil, errors.New("The provided context is invalid: must be an integer >= 0.")
+	}
+
+	ignoreValues, err := cmd.Flags().GetStringArray("ignore")
+	if err != nil {
+		return nil, errors.New("The provided ignore is invalid.")
+	}
+
+	var ignoreRules []k8s.IgnoreRule
+	for _, value := range ignoreValues {
+		rule, err := k8s.ParseIgnoreRule(value)
+		if err != nil {
+			return nil, err
+		}
+
+		ignoreRules = append(ignoreRules, rule)
+	}
+
+	semantic, err := cmd.Flags().GetBool("semantic")
+	if err != nil {
+		return nil, errors.New("The provided semantic is invalid.")
+	}
+
+	mergeKeyValues, err := cmd.Flags().GetStringArray("merge-key")
+	if err != nil {
+		return nil, errors.New("The provided merge-key is invalid.")
+	}
+
+	// Configured merge keys are added after the built-in ones, so that they take precedence.
+	mergeKeys := slices.Clone(k8s.DefaultMergeKeys)
+	for _, value := range mergeKeyValues {
+		mergeKey, err := k8s.ParseMergeKey(value)
+		if err != nil {
+			return nil, err
+		}
+
+		mergeKeys = append(mergeKeys, mergeKey)
+	}
+
+	ignoreReorders, err := cmd.Flags().GetBool("ignore-reorders")
+	if err != nil {
+		return nil, errors.New("The provided ignore-reorders is invalid.")
+	}
+
+	ignoreDefaults, err := cmd.Flags().GetBool("ignore-defaults")
+	if err != nil {
+		return nil, errors.New("The provided ignore-defaults is invalid.")
+	}
+
+	collapseHashSuffixes, err := cmd.Flags().GetBool("collapse-hash-suffixes")
+	if err != nil {
+		return nil, errors.New("The provided collapse-hash-suffixes is invalid.")
+	}
+
+	renameThreshold, err := cmd.Flags().GetFloat64("rename-threshold")
+	if err != nil || renameThreshold < 0 || renameThreshold > 1 {
+		return nil, errors.New("The provided rename-threshold is invalid: must be a number between 0 and 1.")
+	}
+
+	includeFilters, err := parseResourceFilters(cmd, "include")
+	if err != nil {
+		return nil, err
+	}
+
+	excludeFilters, err := parseResourceFilters(cmd, "exclude")
+	if err != nil {
+		return nil, err
+	}
+
+	return &k8s.DiffOptions{
+		SortOrder:            sortOrder,
+		DiffStyle:            diffStyle,
+		ContextLines:         contextLines,
+		IgnoreRules:          ignoreRules,
+		RedactSecrets:        true,
+		Semantic:             semantic,
+		MergeKeys:            mergeKeys,
+		IgnoreReorders:       ignoreReorders,
+		IgnoreDefaults:       ignoreDefaults,
+		Filters:              k8s.ResourceFilters{Include: includeFilters, Exclude: excludeFilters},
+		RenameThreshold:      renameThreshold,
+		CollapseHashSuffixes: collapseHashSuffixes,
+	}, nil
+}
+
+// Parses the resource filters of the flag with the given name.
+func parseResourceFilters(cmd *cobra.Command, flag string) ([]k8s.ResourceFilter, error) {
+	values, err := cmd.Flags().GetStringArray(flag)
+	if err != nil {
+		return nil, errors.New("The provided " + flag + " is invalid.")
+	}
+
+	var filters []k8s.ResourceFilter
+	for _, value := range values {
+		filter, err := k8s.ParseResourceFilter(value)
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
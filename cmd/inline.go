@@ -3,8 +3,6 @@ package cmd
 import (
 	"os"
 
-	k8s "github.com/namoshek/kustomize-diff/kubernetes"
-	kustomize "github.com/namoshek/kustomize-diff/kustomize"
 	utils "github.com/namoshek/kustomize-diff/utils"
 
 	"github.com/spf13/cobra"
@@ -26,34 +24,61 @@ func NewInlineCmd() *cobra.Command {
 
 func init() {
 	rootCmd.AddCommand(inlineCmd)
+
+	inlineCmd.Flags().StringP("output", "O", string(outputFormatText), "Output format: 'text' (plain diffs), 'markdown' (diffs in markdown code blocks), 'patch' (multi-file patch for 'git apply'), 'json' (versioned document for machine consumption) or 'html' (standalone report)")
+	inlineCmd.Flags().Bool("show-secrets", false, "Do not redact the values of Secret data and stringData; only use this locally, as the values are printed in plain text")
+	inlineCmd.Flags().Bool("decode-base64", false, "Decode the values of Secret data and ConfigMap binaryData; text is shown decoded, binary content as size and hash")
+	inlineCmd.Flags().String("color", "auto", "Color text output: 'auto' (if stdout is a terminal and NO_COLOR is not set), 'always' or 'never'")
 }
 
 func runInlineCommand(cmd *cobra.Command, args []string) {
-	// Attempt to create the Kustomizations of the provided directories.
-	pathToOldVersion, pathToNewVersion := args[0], args[1]
+	// Parse and validate the command flags.
+	diffOptions, err := parseDiffOptions(cmd)
+	if err != nil {
+		utils.Logger.Error("Flag validation failed.", zap.Error(err))
+		os.Exit(1)
+	}
+
+	showSecrets, err := cmd.Flags().GetBool("show-secrets")
+	if err != nil {
+		utils.Logger.Error("Reading --show-secrets option failed.")
+		os.Exit(1)
+	}
 
-	kustomizeExecutable, err := cmd.Flags().GetString("kustomize-executable")
+	decodeBase64, err := cmd.Flags().GetBool("decode-base64")
 	if err != nil {
-		utils.Logger.Error("Reading --kustomize-executable option failed.")
+		utils.Logger.Error("Reading --decode-base64 option failed.")
 		os.Exit(1)
 	}
 
-	oldKustomization, newKustomization, err := kustomize.BuildKustomizations(kustomizeExecutable, pathToOldVersion, pathToNewVersion)
+	diffOptions.RedactSecrets = !showSecrets
+	diffOptions.DecodeBase64 = decodeBase64
+
+	outputOptions, err := parseOutputOptions(cmd, os.Stdout)
 	if err != nil {
-		utils.Logger.Error("Building Kustomizations failed.", zap.Error(err))
+		utils.Logger.Error("Flag validation failed.", zap.Error(err))
 		os.Exit(1)
 	}
 
-	// Create a diff of both Kustomizations.
-	diffs, err := k8s.CreateDiffForManifestFiles(oldKustomization, newKustomization)
+	// Patches contain all changes of the raw manifests, including formatting changes, so that they can be applied to the rendered manifests.
+	if outputOptions.Format == outputFormatPatch {
+		diffOptions.Semantic = false
+	}
+
+	// Build the Kustomizations of the provided directories and create a diff of both.
+	pathToOldVersion, pathToNewVersion := args[0], args[1]
+
+	report, err := buildAndDiffKustomizations(cmd, diffOptions, pathToOldVersion, pathToNewVersion)
 	if err != nil {
 		utils.Logger.Error("Creating the diff failed.", zap.Error(err))
 		os.Exit(1)
 	}
 
-	// Iterate the diffs and print them to stdout.
-	for _, diff := range diffs {
-		k8s.PrintDiff(&diff, true, os.Stdout)
+	// Print the diffs to stdout in the requested format.
+	err = printDiffs(report, diffOptions, outputOptions, os.Stdout)
+	if err != nil {
+		utils.Logger.Error("Printing the diff failed.", zap.Error(err))
+		os.Exit(1)
 	}
 
 	os.Exit(0)
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	k8s "github.com/namoshek/kustomize-diff/kubernetes"
+
+	"github.com/spf13/cobra"
+)
+
+// The format in which diffs are printed.
+type outputFormat string
+
+const (
+	outputFormatText     outputFormat = "text"
+	outputFormatMarkdown outputFormat = "markdown"
+	outputFormatPatch    outputFormat = "patch"
+	outputFormatJson     outputFormat = "json"
+	outputFormatHtml     outputFormat = "html"
+)
+
+// Options which control how diffs are printed.
+// The side-by-side view and the field changes are only used for text, markdown and HTML output.
+type outputOptions struct {
+	Format       outputFormat
+	Color        bool
+	SideBySide   bool
+	Width        int
+	Headers      bool
+	FieldChanges bool
+}
+
+// Parses the given string as output format.
+func parseOutputFormat(value string) (outputFormat, error) {
+	switch outputFormat(value) {
+	case outputFormatText, outputFormatMarkdown, outputFormatPatch, outputFormatJson, outputFormatHtml:
+		return outputFormat(value), nil
+	default:
+		return "", errors.New("The output format '" + value + "' is invalid: must be one of 'text', 'markdown', 'patch', 'json' or 'html'.")
+	}
+}
+
+// Parses the output options from the --output and --color flags of the given command.
+// Colors are only used for text output written to the given file.
+func parseOutputOptions(cmd *cobra.Command, file *os.File) (*outputOptions, error) {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return nil, errors.New("The provided output is invalid.")
+	}
+
+	format, err := parseOutputFormat(output)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := parseSharedOutputOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	color, err := cmd.Flags().GetString("color")
+	if err != nil {
+		return nil, errors.New("The provided color is invalid.")
+	}
+
+	useColor, err := shouldUseColor(color, file)
+	if err != nil {
+		return nil, err
+	}
+
+	options.Format = format
+	options.Color = useColor && format == outputFormatText
+
+	return options, nil
+}
+
+// Parses the output options shared by all formats from the --side-by-side, --width, --hide-headers and --field-changes
+// flags of the given command. The format is markdown, unless it is overridden by the caller.
+func parseSharedOutputOptions(cmd *cobra.Command) (*outputOptions, error) {
+	sideBySide, err := cmd.Flags().GetBool("side-by-side")
+	if err != nil {
+		return nil, errors.New("The provided side-by-side is invalid.")
+	}
+
+	width, err := cmd.Flags().GetInt("width")
+	if err != nil || width < 20 {
+		return nil, errors.New("The provided width is invalid: must be an integer >= 20.")
+	}
+
+	hideHeaders, err := cmd.Flags().GetBool("hide-headers")
+	if err != nil {
+		return nil, errors.New("The provided hide-headers is invalid.")
+	}
+
+	fieldChanges, err := cmd.Flags().GetBool("field-changes")
+	if err != nil {
+		return nil, errors.New("The provided field-changes is invalid.")
+	}
+
+	return &outputOptions{
+		Format:       outputFormatMarkdown,
+		SideBySide:   sideBySide,
+		Width:        width,
+		Headers:      !hideHeaders,
+		FieldChanges: fieldChanges,
+	}, nil
+}
+
+// Determines whether colors should be used based on the given mode ('auto', 'always' or 'never').
+// In 'auto' mode, colors are used if the given file is a terminal and the NO_COLOR environment variable is not set.
+func shouldUseColor(mode string, file *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+
+		return isTerminal(file), nil
+	default:
+		return false, errors.New("The color mode '" + mode + "' is invalid: must be one of 'auto', 'always' or 'never'.")
+	}
+}
+
+// Determines whether the given file is a terminal (character device).
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Prints the diffs of the given report according to the given output options.
+// For text and markdown output, the resources which only differ in default values and the number of changed resources
+// removed by filters are printed after the diffs. Patch output does not contain them, so that it can still be applied.
+func printDiffs(report *k8s.DiffReport, diffOptions *k8s.DiffOptions, outputOptions *outputOptions, output io.Writer) error {
+	diffs := report.Diffs
+
+	switch outputOptions.Format {
+	case outputFormatPatch:
+		k8s.PrintPatch(diffs, diffOptions.ContextLines, outputOptions.Headers, output)
+	case outputFormatJson:
+		return k8s.PrintJson(report, output)
+	case outputFormatHtml:
+		sideBySideOptions := createSideBySideOptions(diffOptions, outputOptions)
+
+		return k8s.PrintHtmlReport(report, &k8s.HtmlReportOptions{
+			ContextLines: sideBySideOptions.ContextLines,
+			SideBySide:   outputOptions.SideBySide,
+			FieldChanges: outputOptions.FieldChanges,
+		}, output)
+	case outputFormatMarkdown:
+		for _, diff := range diffs {
+			if outputOptions.Headers {
+				k8s.PrintDiffHeader(&diff, false, output)
+			}
+
+			k8s.PrintNotes(&diff, true, output)
+
+			if outputOptions.FieldChanges {
+				k8s.PrintFieldChanges(&diff, true, output)
+			}
+
+			// Resources whose lists were only reordered or whose references only changed their hash suffix do not have a line diff.
+			if !diff.HasChangedLines() {
+				continue
+			}
+
+			if outputOptions.SideBySide {
+				fmt.Fprintln(output, "```")
+				k8s.PrintSideBySideDiff(&diff, createSideBySideOptions(diffOptions, outputOptions), output)
+				fmt.Fprintln(output, "```")
+			} else {
+				k8s.PrintDiff(&diff, true, output)
+			}
+		}
+
+		k8s.PrintDefaultsOnlyChanges(report.DefaultsOnly, true, output)
+		printFilteredOutNote(report, "_", output)
+	default:
+		for _, diff := range diffs {
+			if outputOptions.Headers {
+				k8s.PrintDiffHeader(&diff, outputOptions.Color, output)
+			}
+
+			k8s.PrintNotes(&diff, false, output)
+
+			if outputOptions.FieldChanges {
+				k8s.PrintFieldChanges(&diff, false, output)
+			}
+
+			if !diff.HasChangedLines() {
+				continue
+			}
+
+			switch {
+			case outputOptions.SideBySide:
+				k8s.PrintSideBySideDiff(&diff, createSideBySideOptions(diffOptions, outputOptions), output)
+			case outputOptions.Color:
+				k8s.PrintColoredDiff(&diff, output)
+			default:
+				k8s.PrintDiff(&diff, false, output)
+			}
+		}
+
+		k8s.PrintDefaultsOnlyChanges(report.DefaultsOnly, false, output)
+		printFilteredOutNote(report, "", output)
+	}
+
+	return nil
+}
+
+// Prints a note with the number of changed resources removed by filters, if there are any.
+// The note is wrapped in the given emphasis, e.g. '_' for markdown.
+func printFilteredOutNote(report *k8s.DiffReport, emphasis string, output io.Writer) {
+	if report.FilteredOut == 0 {
+		return
+	}
+
+	fmt.Fprintf(output, "%s%d changed resources were filtered out.%s\n", emphasis, report.FilteredOut, emphasis)
+}
+
+// Creates the options for side-by-side diffs. All lines are printed for the full diff style.
+func createSideBySideOptions(diffOptions *k8s.DiffOptions, outputOptions *outputOptions) *k8s.SideBySideOptions {
+	contextLines := diffOptions.ContextLines
+	if diffOptions.DiffStyle == k8s.DiffStyleFull {
+		contextLines = -1
+	}
+
+	return &k8s.SideBySideOptions{
+		Width:        outputOptions.Width,
+		ContextLines: contextLines,
+		Color:        outputOptions.Color,
+	}
+}
@@ -30,5 +30,21 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringP("kustomize-executable", "k", "kustomize", "Path to the kustomize binary")
+	rootCmd.PersistentFlags().String("sort", "kind", "Order of the diffs: 'kind' (kind, namespace and name), 'document' (order in the new Kustomization) or 'apply' (Kubernetes apply order)")
+	rootCmd.PersistentFlags().String("diff-style", "unified", "Style of the diffs: 'unified' (changes with context lines and hunk headers) or 'full' (the whole manifest)")
+	rootCmd.PersistentFlags().IntP("context", "U", 3, "Number of context lines around changes in unified diffs")
+	rootCmd.PersistentFlags().StringArray("ignore", nil, "Ignore fields matching the rule '[Kind:]path' when comparing manifests, e.g. 'metadata.annotations[\"checksum/config\"]' (can be repeated)")
+	rootCmd.PersistentFlags().Bool("semantic", true, "Compare canonicalized manifests, so that differences in key order, quoting, indentation, scalar notation and equivalent quantities are not reported; use --semantic=false to compare the raw manifests (always the case for patch output)")
+	rootCmd.PersistentFlags().StringArray("merge-key", nil, "Match the items of lists at '[Kind:]path' by the given key, e.g. 'MyResource:spec.backends=id', in addition to the built-in merge keys of core types (can be repeated)")
+	rootCmd.PersistentFlags().Bool("ignore-reorders", false, "Do not report resources and lists whose items were only reordered")
+	rootCmd.PersistentFlags().Float64("rename-threshold", 0.8, "Minimum similarity (between 0 and 1) of a removed and an added resource of the same kind and namespace to report them as renamed; use 0 to disable rename detection")
+	rootCmd.PersistentFlags().Bool("collapse-hash-suffixes", false, "Match ConfigMaps and Secrets generated by kustomize by their name without hash suffix and report references which only changed their hash suffix as a single note per resource")
+	rootCmd.PersistentFlags().Bool("ignore-defaults", false, "Treat fields set to the default value of the API server as absent and report resources which only differ in such fields separately")
+	rootCmd.PersistentFlags().StringArray("include", nil, "Only diff resources matching the filter 'kind=<glob>', 'namespace=<glob>', 'name=<glob>' or 'label=<selector>' (can be repeated)")
+	rootCmd.PersistentFlags().StringArray("exclude", nil, "Do not diff resources matching the filter 'kind=<glob>', 'namespace=<glob>', 'name=<glob>' or 'label=<selector>' (can be repeated)")
+	rootCmd.PersistentFlags().Bool("side-by-side", false, "Print text, markdown and HTML diffs as side-by-side view of the old and new version")
+	rootCmd.PersistentFlags().Int("width", 160, "Total width of side-by-side diffs in characters")
+	rootCmd.PersistentFlags().Bool("field-changes", false, "Print the list of changed fields, e.g. 'spec.replicas: 1 → 2', before each text, markdown and HTML diff")
+	rootCmd.PersistentFlags().Bool("hide-headers", false, "Do not print a header line with resource identity, change type and line counts before each diff")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Print verbose output during execution")
 }
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	k8s "github.com/namoshek/kustomize-diff/kubernetes"
+	utils "github.com/namoshek/kustomize-diff/utils"
+
+	"github.com/spf13/cobra"
+
+	"go.uber.org/zap"
+)
+
+var summaryCmd = NewSummaryCmd()
+
+func NewSummaryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "summary <pathToOldVersion> <pathToNewVersion>",
+		Short: "Prints a table of the changed resources of two Kustomizations",
+		Long:  `Use this action to get an overview of the changed resources of two Kustomizations without printing the diffs.`,
+		Args:  cobra.MatchAll(cobra.ExactArgs(2), cobra.OnlyValidArgs),
+		Run:   runSummaryCommand,
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(summaryCmd)
+
+	summaryCmd.Flags().StringP("output", "O", string(outputFormatText), "Output format: 'text' (aligned table) or 'markdown' (markdown table)")
+	summaryCmd.Flags().Bool("name-only", false, "Print only the kind and name of changed resources")
+	summaryCmd.Flags().Bool("name-status", false, "Print only the change status, kind and name of changed resources")
+}
+
+func runSummaryCommand(cmd *cobra.Command, args []string) {
+	// Parse and validate the command flags.
+	diffOptions, err := parseDiffOptions(cmd)
+	if err != nil {
+		utils.Logger.Error("Flag validation failed.", zap.Error(err))
+		os.Exit(1)
+	}
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil || (output != string(outputFormatText) && output != string(outputFormatMarkdown)) {
+		utils.Logger.Error("Flag validation failed.", zap.Error(errors.New("The provided output is invalid: must be one of 'text' or 'markdown'.")))
+		os.Exit(1)
+	}
+
+	nameOnly, err := cmd.Flags().GetBool("name-only")
+	if err != nil {
+		utils.Logger.Error("Reading --name-only option failed.")
+		os.Exit(1)
+	}
+
+	nameStatus, err := cmd.Flags().GetBool("name-status")
+	if err != nil {
+		utils.Logger.Error("Reading --name-status option failed.")
+		os.Exit(1)
+	}
+
+	if nameOnly && nameStatus {
+		utils.Logger.Error("Flag validation failed.", zap.Error(errors.New("The flags --name-only and --name-status cannot be combined.")))
+		os.Exit(1)
+	}
+
+	// Build the Kustomizations of the provided directories and create a diff of both.
+	pathToOldVersion, pathToNewVersion := args[0], args[1]
+
+	report, err := buildAndDiffKustomizations(cmd, diffOptions, pathToOldVersion, pathToNewVersion)
+	if err != nil {
+		utils.Logger.Error("Creating the diff failed.", zap.Error(err))
+		os.Exit(1)
+	}
+
+	// Print the summary to stdout in the requested variant.
+	switch {
+	case nameOnly:
+		k8s.PrintNameOnly(report.Diffs, os.Stdout)
+	case nameStatus:
+		k8s.PrintNameStatus(report.Diffs, os.Stdout)
+	default:
+		k8s.PrintSummaryTable(report, output == string(outputFormatMarkdown), os.Stdout)
+	}
+
+	os.Exit(0)
+}
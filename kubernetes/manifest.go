@@ -1,26 +1,121 @@
 package kubernetes
 
 import (
-	"fmt"
-
-	"github.com/namoshek/kustomize-diff/utils"
+	"strings"
 )
 
 // A manifest describes a Kubernetes object with the most important parameters and its content.
+// The index is the zero-based position of the manifest within the Kustomization it was parsed from and the line is
+// the one-based line number at which its document text starts. The source content is the content before the
+// canonicalization of a semantic comparison, i.e. in its original key order, but with ignored fields removed and
+// secrets redacted. The raw content is the original text of the document, which is never changed.
 type Manifest struct {
-	ApiVersion string
-	Kind       string
-	Name       string
-	Namespace  string
-	Content    string
+	ApiVersion    string
+	Kind          string
+	Name          string
+	Namespace     string
+	Content       string
+	SourceContent string
+	RawContent    string
+	Index         int
+	Line          int
+}
+
+// The identity of a Kubernetes resource, consisting of its group, version, kind, namespace and name.
+type ResourceID struct {
+	Group     string
+	Version   string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// This type contains the resource identity (without version) as key and the manifest itself as value.
+type ManifestMap map[ResourceID]Manifest
+
+// Retrieves the resource identity of the manifest.
+func (m Manifest) ID() ResourceID {
+	group, version := SplitApiVersion(m.ApiVersion)
+
+	return ResourceID{
+		Group:     group,
+		Version:   version,
+		Kind:      m.Kind,
+		Namespace: m.Namespace,
+		Name:      m.Name,
+	}
+}
+
+// Retrieves the source content of the manifest, or its content if the source content is unknown.
+func (m Manifest) Source() string {
+	if m.SourceContent == "" {
+		return m.Content
+	}
+
+	return m.SourceContent
+}
+
+// Retrieves the raw content of the manifest, or its content if the raw content is unknown.
+func (m Manifest) Raw() string {
+	if m.RawContent == "" {
+		return m.Content
+	}
+
+	return m.RawContent
+}
+
+// Retrieves the key which is used to match manifests in a manifest map.
+// The key is the resource identity without version, so that an apiVersion bump is treated as modification.
+func (m Manifest) Key() ResourceID {
+	return m.ID().WithoutVersion()
+}
+
+// Returns a copy of the resource identity with an empty version.
+func (id ResourceID) WithoutVersion() ResourceID {
+	id.Version = ""
+
+	return id
+}
+
+// Returns the apiVersion of the resource identity, e.g. 'apps/v1' or 'v1' for the core group.
+func (id ResourceID) ApiVersion() string {
+	if id.Group == "" {
+		return id.Version
+	}
+
+	return id.Group + "/" + id.Version
+}
+
+// Returns the namespaced name of the resource, e.g. 'my-namespace/my-app' or only 'my-app' for cluster-scoped resources.
+func (id ResourceID) NamespacedName() string {
+	if id.Namespace == "" {
+		return id.Name
+	}
+
+	return id.Namespace + "/" + id.Name
+}
+
+// Returns a readable representation of the resource identity, e.g. 'apps/v1 Deployment my-namespace/my-app'.
+func (id ResourceID) String() string {
+	return strings.TrimSpace(id.ApiVersion() + " " + id.Kind + " " + id.NamespacedName())
+}
+
+// Returns a relative file path which represents the resource, e.g. 'apps_v1/Deployment/my-namespace/my-app.yaml'.
+// The namespace is omitted for cluster-scoped resources.
+func (id ResourceID) FilePath() string {
+	segments := []string{strings.ReplaceAll(id.ApiVersion(), "/", "_"), id.Kind}
+	if id.Namespace != "" {
+		segments = append(segments, id.Namespace)
+	}
+
+	return strings.Join(append(segments, id.Name+".yaml"), "/")
 }
 
-// This type contains the manifest hash as key and the manifest itself as value.
-type ManifestMap map[string]Manifest
+// Splits the given apiVersion into group and version. The core group is returned as empty string.
+func SplitApiVersion(apiVersion string) (string, string) {
+	if group, version, found := strings.Cut(apiVersion, "/"); found {
+		return group, version
+	}
 
-// Calculates the MD5 hash for a manifest header (apiVersion, kind, name and namespace).
-// Can be used to compare manifests.
-func (m Manifest) CalculateHash() string {
-	input := fmt.Sprintf("apiVersion: '%s', kind: '%s', name: '%s', namespace: '%s'", m.ApiVersion, m.Kind, m.Name, m.Namespace)
-	return utils.CalculateMD5AsString(input)
+	return "", apiVersion
 }
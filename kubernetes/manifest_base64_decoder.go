@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Decodes the base64 encoded values of Secret.data and ConfigMap.binaryData, so that changes are readable.
+// Values which are valid UTF-8 are replaced by the decoded text, binary values by a summary of their size and hash,
+// e.g. 'binary: 1024 bytes, sha256:0123456789ab'. Values which are not valid base64 are kept.
+func decodeBase64Data(manifest *Manifest, document *yaml.Node) bool {
+	group, _ := SplitApiVersion(manifest.ApiVersion)
+	if group != "" {
+		return false
+	}
+
+	field := ""
+	switch manifest.Kind {
+	case "Secret":
+		field = "data"
+	case "ConfigMap":
+		field = "binaryData"
+	default:
+		return false
+	}
+
+	decoded := false
+	for _, root := range document.Content {
+		values := mappingValue(root, field)
+		if values == nil || values.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for i := 1; i < len(values.Content); i += 2 {
+			value := values.Content[i]
+			if value.Kind != yaml.ScalarNode {
+				continue
+			}
+
+			content, err := base64.StdEncoding.DecodeString(value.Value)
+			if err != nil {
+				continue
+			}
+
+			value.Value, value.Tag, value.Style = describeDecodedValue(content), "!!str", 0
+			decoded = true
+		}
+	}
+
+	return decoded
+}
+
+// Returns the given decoded content as text if it is valid UTF-8, otherwise a summary of its size and hash.
+func describeDecodedValue(content []byte) string {
+	if utf8.Valid(content) {
+		return string(content)
+	}
+
+	hash := sha256.Sum256(content)
+
+	return fmt.Sprintf("binary: %d bytes, sha256:%s", len(content), hex.EncodeToString(hash[:])[:12])
+}
@@ -0,0 +1,46 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDecodeBase64DataDecodesTextAndSummarizesBinaryContent(t *testing.T) {
+	content := "binaryData:\n  text: aGVsbG8Kd29ybGQK\n  blob: AAECAwT/\n  invalid: not-base64!\n"
+	expectedContent := "binaryData:\n  text: |\n    hello\n    world\n  blob: 'binary: 6 bytes, sha256:40d2af4d7ac4'\n  invalid: not-base64!\n"
+
+	var document yaml.Node
+	_ = yaml.Unmarshal([]byte(content), &document)
+
+	if !decodeBase64Data(&Manifest{ApiVersion: "v1", Kind: "ConfigMap"}, &document) {
+		t.Fatal("Decoding should report that the document was changed.")
+	}
+
+	result, _ := encodeYamlDocument(&document)
+	if result != expectedContent {
+		t.Fatal("The base64 values should be decoded. Result:\n" + result)
+	}
+}
+
+func TestDecodeBase64DataOnlyDecodesSecretDataAndConfigMapBinaryData(t *testing.T) {
+	tests := []struct {
+		manifest Manifest
+		content  string
+		expected bool
+	}{
+		{Manifest{ApiVersion: "v1", Kind: "Secret"}, "data:\n  a: YQ==\n", true},
+		{Manifest{ApiVersion: "v1", Kind: "Secret"}, "stringData:\n  a: YQ==\n", false},
+		{Manifest{ApiVersion: "v1", Kind: "ConfigMap"}, "data:\n  a: YQ==\n", false},
+		{Manifest{ApiVersion: "example.com/v1", Kind: "Secret"}, "data:\n  a: YQ==\n", false},
+	}
+
+	for _, test := range tests {
+		var document yaml.Node
+		_ = yaml.Unmarshal([]byte(test.content), &document)
+
+		if decodeBase64Data(&test.manifest, &document) != test.expected {
+			t.Fatal("Only Secret.data and ConfigMap.binaryData should be decoded.", test.manifest, test.content)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package kubernetes
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Keys of mappings whose values are always strings, e.g. labels and annotations.
+var stringMapKeys = []string{"labels", "annotations", "matchLabels", "nodeSelector"}
+
+// Kinds whose top-level data mappings contain only strings.
+var stringDataKinds = []string{"ConfigMap", "Secret"}
+
+// Canonicalizes the given YAML document, so that semantically equal documents are encoded identically.
+// Mapping keys are sorted, comments and styles (quoting, flow and block scalars) are removed and numbers,
+// booleans and nulls are written in their canonical form. Scalars in string contexts like labels, annotations,
+// ConfigMap data or environment variable values are treated as strings, e.g. 80 equals "80" there.
+// Always returns true, as the document needs to be re-encoded in any case.
+func canonicalizeDocument(manifest *Manifest, document *yaml.Node) bool {
+	document.HeadComment, document.LineComment, document.FootComment = "", "", ""
+
+	for _, node := range document.Content {
+		canonicalizeNode(manifest, node, nil)
+	}
+
+	return true
+}
+
+// Canonicalizes the given node and all of its children. The path contains the keys of all parent mappings,
+// with '[]' for sequence items.
+func canonicalizeNode(manifest *Manifest, node *yaml.Node, path []string) {
+	node.HeadComment, node.LineComment, node.FootComment = "", "", ""
+	node.Style = 0
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if isEmbeddedFileLocation(manifest, path) {
+			if content, ok := canonicalizeEmbeddedFile(path[1], node.Value); ok {
+				node.Value = content
+				if strings.Contains(strings.TrimSuffix(content, "\n"), "\n") {
+					node.Style = yaml.LiteralStyle
+				}
+
+				return
+			}
+		}
+
+		if isStringContext(manifest, path) {
+			canonicalizeStringScalar(node)
+		} else {
+			canonicalizeScalar(node)
+		}
+	case yaml.MappingNode:
+		pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			key.HeadComment, key.LineComment, key.FootComment = "", "", ""
+			key.Style = 0
+
+			canonicalizeNode(manifest, value, append(slices.Clip(path), key.Value))
+			pairs = append(pairs, [2]*yaml.Node{key, value})
+		}
+
+		slices.SortStableFunc(pairs, func(a, b [2]*yaml.Node) int { return cmp.Compare(a[0].Value, b[0].Value) })
+
+		node.Content = node.Content[:0]
+		for _, pair := range pairs {
+			node.Content = append(node.Content, pair[0], pair[1])
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			canonicalizeNode(manifest, item, append(slices.Clip(path), "[]"))
+		}
+	}
+}
+
+// Determines whether the value at the given path is always a string.
+func isStringContext(manifest *Manifest, path []string) bool {
+	length := len(path)
+
+	switch {
+	case length >= 2 && slices.Contains(stringMapKeys, path[length-2]):
+		return true
+	case length == 2 && slices.Contains(stringDataKinds, manifest.Kind) && (path[0] == "data" || path[0] == "stringData" || path[0] == "binaryData"):
+		return true
+	case length >= 3 && path[length-3] == "env" && path[length-2] == "[]" && path[length-1] == "value":
+		return true
+	default:
+		return false
+	}
+}
+
+// Converts the given scalar into a string, e.g. 80 and "80" are both encoded as "80". Null is treated as empty string.
+func canonicalizeStringScalar(node *yaml.Node) {
+	if node.Tag == "!!null" {
+		node.Value = ""
+	}
+
+	node.Tag = "!!str"
+}
+
+// Writes numbers, booleans and nulls of the given scalar in their canonical form, e.g. 0x10 as 16 and 1.0 as 1.
+func canonicalizeScalar(node *yaml.Node) {
+	switch node.ShortTag() {
+	case "!!int":
+		if value, err := strconv.ParseInt(strings.ReplaceAll(node.Value, "_", ""), 0, 64); err == nil {
+			node.Value = strconv.FormatInt(value, 10)
+		}
+	case "!!float":
+		value, err := strconv.ParseFloat(strings.ReplaceAll(node.Value, "_", ""), 64)
+		if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+			return
+		}
+
+		// JSON, and therefore Kubernetes, does not distinguish between integral floats and integers.
+		if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+			node.Tag, node.Value = "!!int", strconv.FormatInt(int64(value), 10)
+		} else {
+			node.Value = strconv.FormatFloat(value, 'g', -1, 64)
+		}
+	case "!!bool":
+		node.Value = strconv.FormatBool(strings.EqualFold(node.Value, "true"))
+	case "!!null":
+		node.Value = "null"
+	}
+}
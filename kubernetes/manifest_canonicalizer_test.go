@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCanonicalizeDocumentSortsKeysAndRemovesFormatting(t *testing.T) {
+	content := "kind: Service\napiVersion: v1\nmetadata: {name: 'web'}  # the name\nspec:\n  ports:\n  - port: 0x50\n    name: \"http\"\n"
+	expectedContent := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports:\n    - name: http\n      port: 80\n"
+
+	result := canonicalize(t, &Manifest{Kind: "Service"}, content)
+
+	if result != expectedContent {
+		t.Fatal("The document should be canonicalized. Result:\n" + result)
+	}
+}
+
+func TestCanonicalizeDocumentNormalizesScalars(t *testing.T) {
+	content := "a: 1.0\nb: 0.50\nc: TRUE\nd: ~\ne: 1_000\nf: 1.5e3\n"
+	expectedContent := "a: 1\nb: 0.5\nc: true\nd: null\ne: 1000\nf: 1500\n"
+
+	result := canonicalize(t, &Manifest{Kind: "Pod"}, content)
+
+	if result != expectedContent {
+		t.Fatal("Scalars should be written in their canonical form. Result:\n" + result)
+	}
+}
+
+func TestCanonicalizeDocumentTreatsScalarsInStringContextsAsStrings(t *testing.T) {
+	contents := []string{
+		"metadata:\n  labels:\n    port: 80\n    enabled: true\n  annotations:\n    empty:\ndata:\n  port: 80\nspec:\n  env:\n    - name: PORT\n      value: 80\n",
+		"metadata:\n  labels:\n    port: \"80\"\n    enabled: \"true\"\n  annotations:\n    empty: \"\"\ndata:\n  port: '80'\nspec:\n  env:\n    - name: PORT\n      value: \"80\"\n",
+	}
+
+	first := canonicalize(t, &Manifest{Kind: "ConfigMap"}, contents[0])
+	second := canonicalize(t, &Manifest{Kind: "ConfigMap"}, contents[1])
+
+	if first != second {
+		t.Fatal("Scalars in string contexts should be canonicalized as strings. Results:\n" + first + "\n" + second)
+	}
+}
+
+func TestCanonicalizeDocumentKeepsTypesOutsideOfStringContexts(t *testing.T) {
+	first := canonicalize(t, &Manifest{Kind: "Deployment"}, "spec:\n  replicas: 1\n")
+	second := canonicalize(t, &Manifest{Kind: "Deployment"}, "spec:\n  replicas: \"1\"\n")
+
+	if first == second {
+		t.Fatal("Scalars outside of string contexts should keep their type. Result:\n" + first)
+	}
+}
+
+func canonicalize(t *testing.T, manifest *Manifest, content string) string {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		t.Fatal("Parsing the test manifest should not fail.", err)
+	}
+
+	canonicalizeDocument(manifest, &document)
+
+	result, err := encodeYamlDocument(&document)
+	if err != nil {
+		t.Fatal("Encoding the test manifest should not fail.", err)
+	}
+
+	return result
+}
@@ -31,12 +31,12 @@ func FilterUnchangedManifests(oldManifests *ManifestMap, newManifests *ManifestM
 	return &filteredOldManifests, &filteredNewManifests
 }
 
-// Returns a list of MD5 hashes for all manifests in the given input maps.
-// If a manifest with identical MD5 hash is present in both maps, the hash is returned only once.
-func GetUniqueManifestHashes(old *ManifestMap, new *ManifestMap) *[]string {
+// Returns a list of resource identities for all manifests in the given input maps.
+// If a manifest with identical identity is present in both maps, the identity is returned only once.
+func GetUniqueResourceIDs(old *ManifestMap, new *ManifestMap) *[]ResourceID {
 	oldKeys := slices.Collect(maps.Keys(*old))
 	keys := append(oldKeys, slices.Collect(maps.Keys(*new))...)
-	slice := set.From[string](keys).Slice()
+	slice := set.From[ResourceID](keys).Slice()
 
 	return &slice
 }
@@ -10,11 +10,11 @@ func TestFilterUnchangedManifestsDoesNotFilterNewManifests(t *testing.T) {
 		Namespace:  "my-namespace",
 		Content:    "apiVersion: v1\nkind: Service\nmetadata:\n  name: backend\n  namespace: my-namespace",
 	}
-	manifestHash := manifest.CalculateHash()
+	manifestKey := manifest.Key()
 
 	oldManifests := make(ManifestMap)
 	newManifests := ManifestMap{
-		manifestHash: manifest,
+		manifestKey: manifest,
 	}
 
 	oldFilteredManifests, newFilteredManifests := FilterUnchangedManifests(&oldManifests, &newManifests)
@@ -32,10 +32,10 @@ func TestFilterUnchangedManifestsDoesNotFilterRemovedManifests(t *testing.T) {
 		Namespace:  "my-namespace",
 		Content:    "apiVersion: v1\nkind: Service\nmetadata:\n  name: backend\n  namespace: my-namespace",
 	}
-	manifestHash := manifest.CalculateHash()
+	manifestKey := manifest.Key()
 
 	oldManifests := ManifestMap{
-		manifestHash: manifest,
+		manifestKey: manifest,
 	}
 	newManifests := make(ManifestMap)
 
@@ -54,10 +54,10 @@ func TestFilterUnchangedManifestsDoesNotFilterChangedManifests(t *testing.T) {
 		Namespace:  "my-namespace",
 		Content:    "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  replicas: 1",
 	}
-	oldManifestHash := oldManifest.CalculateHash()
+	oldManifestKey := oldManifest.Key()
 
 	oldManifests := ManifestMap{
-		oldManifestHash: oldManifest,
+		oldManifestKey: oldManifest,
 	}
 
 	newManifest := Manifest{
@@ -67,10 +67,10 @@ func TestFilterUnchangedManifestsDoesNotFilterChangedManifests(t *testing.T) {
 		Namespace:  "my-namespace",
 		Content:    "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  replicas: 2",
 	}
-	newManifestHash := newManifest.CalculateHash()
+	newManifestKey := newManifest.Key()
 
 	newManifests := ManifestMap{
-		newManifestHash: newManifest,
+		newManifestKey: newManifest,
 	}
 
 	oldFilteredManifests, newFilteredManifests := FilterUnchangedManifests(&oldManifests, &newManifests)
@@ -88,10 +88,10 @@ func TestFilterUnchangedManifestsDoesFilterUnchangedManifests(t *testing.T) {
 		Namespace:  "my-namespace",
 		Content:    "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  replicas: 1",
 	}
-	oldManifestHash := oldManifest.CalculateHash()
+	oldManifestKey := oldManifest.Key()
 
 	oldManifests := ManifestMap{
-		oldManifestHash: oldManifest,
+		oldManifestKey: oldManifest,
 	}
 
 	newManifest := Manifest{
@@ -101,10 +101,10 @@ func TestFilterUnchangedManifestsDoesFilterUnchangedManifests(t *testing.T) {
 		Namespace:  "my-namespace",
 		Content:    "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  replicas: 1",
 	}
-	newManifestHash := newManifest.CalculateHash()
+	newManifestKey := newManifest.Key()
 
 	newManifests := ManifestMap{
-		newManifestHash: newManifest,
+		newManifestKey: newManifest,
 	}
 
 	oldFilteredManifests, newFilteredManifests := FilterUnchangedManifests(&oldManifests, &newManifests)
@@ -114,56 +114,56 @@ func TestFilterUnchangedManifestsDoesFilterUnchangedManifests(t *testing.T) {
 	}
 }
 
-func TestGetUniqueManifestHashesReturnsHashesOfFirstMap(t *testing.T) {
+func TestGetUniqueResourceIDsReturnsResourceIDsOfFirstMap(t *testing.T) {
 	manifest := Manifest{
 		ApiVersion: "v1",
 		Kind:       "Service",
 		Name:       "backend",
 		Namespace:  "my-namespace",
 	}
-	manifestHash := manifest.CalculateHash()
+	manifestKey := manifest.Key()
 
 	oldManifests := ManifestMap{
-		manifestHash: manifest,
+		manifestKey: manifest,
 	}
 	newManifests := make(ManifestMap)
 
-	uniqueManifestHashes := GetUniqueManifestHashes(&oldManifests, &newManifests)
+	uniqueResourceIDs := GetUniqueResourceIDs(&oldManifests, &newManifests)
 
-	if len(*uniqueManifestHashes) != 1 || (*uniqueManifestHashes)[0] != manifestHash {
-		t.Fatal("The unique manifest hashes from the first map should be returned.")
+	if len(*uniqueResourceIDs) != 1 || (*uniqueResourceIDs)[0] != manifestKey {
+		t.Fatal("The unique resource identities from the first map should be returned.")
 	}
 }
 
-func TestGetUniqueManifestHashesReturnsHashesOfSecondMap(t *testing.T) {
+func TestGetUniqueResourceIDsReturnsResourceIDsOfSecondMap(t *testing.T) {
 	manifest := Manifest{
 		ApiVersion: "v1",
 		Kind:       "Service",
 		Name:       "backend",
 		Namespace:  "my-namespace",
 	}
-	manifestHash := manifest.CalculateHash()
+	manifestKey := manifest.Key()
 
 	oldManifests := make(ManifestMap)
 	newManifests := ManifestMap{
-		manifestHash: manifest,
+		manifestKey: manifest,
 	}
 
-	uniqueManifestHashes := GetUniqueManifestHashes(&oldManifests, &newManifests)
+	uniqueResourceIDs := GetUniqueResourceIDs(&oldManifests, &newManifests)
 
-	if len(*uniqueManifestHashes) != 1 || (*uniqueManifestHashes)[0] != manifestHash {
-		t.Fatal("The unique manifest hashes from the second map should be returned.")
+	if len(*uniqueResourceIDs) != 1 || (*uniqueResourceIDs)[0] != manifestKey {
+		t.Fatal("The unique resource identities from the second map should be returned.")
 	}
 }
 
-func TestGetUniqueManifestHashesReturnsHashesOfBothMapsWithoutDuplicates(t *testing.T) {
+func TestGetUniqueResourceIDsReturnsResourceIDsOfBothMapsWithoutDuplicates(t *testing.T) {
 	manifest1 := Manifest{
 		ApiVersion: "v1",
 		Kind:       "Service",
 		Name:       "backend",
 		Namespace:  "my-namespace",
 	}
-	manifest1Hash := manifest1.CalculateHash()
+	manifest1Key := manifest1.Key()
 
 	manifest2 := Manifest{
 		ApiVersion: "apps/v1",
@@ -171,28 +171,28 @@ func TestGetUniqueManifestHashesReturnsHashesOfBothMapsWithoutDuplicates(t *test
 		Name:       "frontend",
 		Namespace:  "my-namespace",
 	}
-	manifest2Hash := manifest2.CalculateHash()
+	manifest2Key := manifest2.Key()
 
 	manifest3 := Manifest{
 		ApiVersion: "apps/v1",
 		Kind:       "StatefulSet",
 		Name:       "database",
 	}
-	manifest3Hash := manifest3.CalculateHash()
+	manifest3Key := manifest3.Key()
 
 	oldManifests := ManifestMap{
-		manifest1Hash: manifest1,
-		manifest2Hash: manifest2,
+		manifest1Key: manifest1,
+		manifest2Key: manifest2,
 	}
 
 	newManifests := ManifestMap{
-		manifest2Hash: manifest2,
-		manifest3Hash: manifest3,
+		manifest2Key: manifest2,
+		manifest3Key: manifest3,
 	}
 
-	uniqueManifestHashes := GetUniqueManifestHashes(&oldManifests, &newManifests)
+	uniqueResourceIDs := GetUniqueResourceIDs(&oldManifests, &newManifests)
 
-	if len(*uniqueManifestHashes) != 3 {
-		t.Fatal("The unique manifest hashes should be returned from both maps without duplicates.")
+	if len(*uniqueResourceIDs) != 3 {
+		t.Fatal("The unique resource identities should be returned from both maps without duplicates.")
 	}
 }
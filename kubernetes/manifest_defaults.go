@@ -0,0 +1,184 @@
+package kubernetes
+
+import (
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A value which is applied by the API server if the field at the given location is not set.
+type defaultValue struct {
+	Pattern fieldPattern
+	Value   string
+}
+
+// Parses a default value of the form '[Kind:]path=value' and panics if it is invalid. Only used for the built-in defaults.
+func mustParseDefaultValue(value string) defaultValue {
+	index := strings.LastIndex(value, "=")
+	if index < 0 {
+		panic("The default value '" + value + "' is invalid: must be of the form '[Kind:]path=value'.")
+	}
+
+	return defaultValue{Pattern: mustParseFieldPattern(value[:index]), Value: value[index+1:]}
+}
+
+// The API groups of the resources the default values apply to.
+var defaultValueGroups = []string{"", "apps", "batch"}
+
+// The default values of common fields of core, apps and batch resources. Fields of pod specs and containers are
+// matched by the end of their location, so that they apply to pods as well as to pod templates of workloads.
+var defaultValues = []defaultValue{
+	// Services
+	mustParseDefaultValue("Service:spec.type=ClusterIP"),
+	mustParseDefaultValue("Service:spec.sessionAffinity=None"),
+	mustParseDefaultValue("Service:spec.internalTrafficPolicy=Cluster"),
+	mustParseDefaultValue("Service:spec.ipFamilyPolicy=SingleStack"),
+	mustParseDefaultValue("Service:spec.ports[*].protocol=TCP"),
+
+	// Workloads
+	mustParseDefaultValue("Deployment:spec.replicas=1"),
+	mustParseDefaultValue("Deployment:spec.revisionHistoryLimit=10"),
+	mustParseDefaultValue("Deployment:spec.progressDeadlineSeconds=600"),
+	mustParseDefaultValue("Deployment:spec.strategy.type=RollingUpdate"),
+	mustParseDefaultValue("Deployment:spec.strategy.rollingUpdate.maxSurge=25%"),
+	mustParseDefaultValue("Deployment:spec.strategy.rollingUpdate.maxUnavailable=25%"),
+	mustParseDefaultValue("ReplicaSet:spec.replicas=1"),
+	mustParseDefaultValue("StatefulSet:spec.replicas=1"),
+	mustParseDefaultValue("StatefulSet:spec.revisionHistoryLimit=10"),
+	mustParseDefaultValue("StatefulSet:spec.podManagementPolicy=OrderedReady"),
+	mustParseDefaultValue("StatefulSet:spec.updateStrategy.type=RollingUpdate"),
+	mustParseDefaultValue("StatefulSet:spec.updateStrategy.rollingUpdate.partition=0"),
+	mustParseDefaultValue("DaemonSet:spec.revisionHistoryLimit=10"),
+	mustParseDefaultValue("DaemonSet:spec.updateStrategy.type=RollingUpdate"),
+	mustParseDefaultValue("DaemonSet:spec.updateStrategy.rollingUpdate.maxUnavailable=1"),
+	mustParseDefaultValue("DaemonSet:spec.updateStrategy.rollingUpdate.maxSurge=0"),
+
+	// Jobs
+	mustParseDefaultValue("Job:spec.parallelism=1"),
+	mustParseDefaultValue("Job:spec.backoffLimit=6"),
+	mustParseDefaultValue("Job:spec.completionMode=NonIndexed"),
+	mustParseDefaultValue("Job:spec.suspend=false"),
+	mustParseDefaultValue("CronJob:spec.concurrencyPolicy=Allow"),
+	mustParseDefaultValue("CronJob:spec.suspend=false"),
+	mustParseDefaultValue("CronJob:spec.successfulJobsHistoryLimit=3"),
+	mustParseDefaultValue("CronJob:spec.failedJobsHistoryLimit=1"),
+	mustParseDefaultValue("CronJob:spec.jobTemplate.spec.parallelism=1"),
+	mustParseDefaultValue("CronJob:spec.jobTemplate.spec.backoffLimit=6"),
+	mustParseDefaultValue("CronJob:spec.jobTemplate.spec.completionMode=NonIndexed"),
+	mustParseDefaultValue("CronJob:spec.jobTemplate.spec.suspend=false"),
+
+	// Pod specs
+	mustParseDefaultValue("spec.dnsPolicy=ClusterFirst"),
+	mustParseDefaultValue("spec.schedulerName=default-scheduler"),
+	mustParseDefaultValue("spec.terminationGracePeriodSeconds=30"),
+	mustParseDefaultValue("spec.enableServiceLinks=true"),
+	mustParseDefaultValue("Pod:spec.restartPolicy=Always"),
+	mustParseDefaultValue("template.spec.restartPolicy=Always"),
+
+	// Containers
+	mustParseDefaultValue("containers[*].terminationMessagePath=/dev/termination-log"),
+	mustParseDefaultValue("containers[*].terminationMessagePolicy=File"),
+	mustParseDefaultValue("containers[*].ports[*].protocol=TCP"),
+	mustParseDefaultValue("initContainers[*].terminationMessagePath=/dev/termination-log"),
+	mustParseDefaultValue("initContainers[*].terminationMessagePolicy=File"),
+	mustParseDefaultValue("initContainers[*].ports[*].protocol=TCP"),
+	mustParseDefaultValue("*Probe.timeoutSeconds=1"),
+	mustParseDefaultValue("*Probe.periodSeconds=10"),
+	mustParseDefaultValue("*Probe.successThreshold=1"),
+	mustParseDefaultValue("*Probe.failureThreshold=3"),
+	mustParseDefaultValue("httpGet.scheme=HTTP"),
+	mustParseDefaultValue("fieldRef.apiVersion=v1"),
+	mustParseDefaultValue("configMap.defaultMode=420"),
+	mustParseDefaultValue("secret.defaultMode=420"),
+	mustParseDefaultValue("projected.defaultMode=420"),
+}
+
+// Determines whether the given node only contains default values for the given location of the given manifest.
+// Mappings are default values if they are not empty and all of their fields are default values, e.g. 'strategy: {type: RollingUpdate}'.
+func isDefaultValue(manifest *Manifest, location []string, node *yaml.Node) bool {
+	group, _ := SplitApiVersion(manifest.ApiVersion)
+	if !slices.Contains(defaultValueGroups, group) {
+		return false
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return slices.ContainsFunc(defaultValues, func(d defaultValue) bool {
+			return d.Value == node.Value && d.Pattern.matches(manifest, location)
+		})
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			return false
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if !isDefaultValue(manifest, append(slices.Clip(location), node.Content[i].Value), node.Content[i+1]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+// Removes the fields with default values which are only set in one of the given nodes, so that explicitly set
+// defaults are equal to absent fields. Returns the field changes describing the removed fields.
+func removeDefaultValues(manifest *Manifest, mergeKeys []MergeKey, old *yaml.Node, new *yaml.Node) []FieldChange {
+	var changes []FieldChange
+	removeDefaultValuesFromNodes(manifest, mergeKeys, "", nil, old, new, &changes)
+
+	return changes
+}
+
+// Removes the one-sided default values within the given nodes at the given path and location and appends the removed
+// fields to the given list.
+func removeDefaultValuesFromNodes(manifest *Manifest, mergeKeys []MergeKey, path string, location []string, old *yaml.Node, new *yaml.Node, changes *[]FieldChange) {
+	if old == nil || new == nil || old.Kind != new.Kind {
+		return
+	}
+
+	switch old.Kind {
+	case yaml.MappingNode:
+		keys := mappingKeys(old)
+		for _, key := range mappingKeys(new) {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+
+		for _, key := range keys {
+			keyPath, keyLocation := appendKeyToPath(path, key), append(slices.Clip(location), key)
+			oldValue, newValue := mappingValue(old, key), mappingValue(new, key)
+
+			switch {
+			case oldValue == nil && isDefaultValue(manifest, keyLocation, newValue):
+				removeMappingKey(new, key)
+				*changes = append(*changes, FieldChange{Path: keyPath, ChangeType: ChangeTypeAdded, NewValue: decodeNode(newValue)})
+			case newValue == nil && isDefaultValue(manifest, keyLocation, oldValue):
+				removeMappingKey(old, key)
+				*changes = append(*changes, FieldChange{Path: keyPath, ChangeType: ChangeTypeRemoved, OldValue: decodeNode(oldValue)})
+			default:
+				removeDefaultValuesFromNodes(manifest, mergeKeys, keyPath, keyLocation, oldValue, newValue, changes)
+			}
+		}
+	case yaml.SequenceNode:
+		itemLocation := append(slices.Clip(location), "[]")
+		for _, pair := range matchSequenceItems(manifest, mergeKeys, location, old, new) {
+			removeDefaultValuesFromNodes(manifest, mergeKeys, path+pair.Selector, itemLocation, pair.Old, pair.New, changes)
+		}
+	}
+}
+
+// Removes the given key from the given mapping.
+func removeMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = slices.Delete(node.Content, i, i+2)
+
+			return
+		}
+	}
+}
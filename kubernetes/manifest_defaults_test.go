@@ -0,0 +1,60 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestIsDefaultValueMatchesKnownDefaults(t *testing.T) {
+	tests := []struct {
+		manifest Manifest
+		location []string
+		content  string
+		expected bool
+	}{
+		{Manifest{ApiVersion: "v1", Kind: "Service"}, []string{"spec", "ports", "[]", "protocol"}, "TCP", true},
+		{Manifest{ApiVersion: "v1", Kind: "Service"}, []string{"spec", "ports", "[]", "protocol"}, "UDP", false},
+		{Manifest{ApiVersion: "apps/v1", Kind: "Deployment"}, []string{"spec", "replicas"}, "1", true},
+		{Manifest{ApiVersion: "apps/v1", Kind: "StatefulSet"}, []string{"spec", "template", "spec", "containers", "[]", "livenessProbe", "periodSeconds"}, "10", true},
+		{Manifest{ApiVersion: "apps/v1", Kind: "Deployment"}, []string{"spec", "strategy"}, "type: RollingUpdate\nrollingUpdate:\n  maxSurge: 25%\n", true},
+		{Manifest{ApiVersion: "apps/v1", Kind: "Deployment"}, []string{"spec", "strategy"}, "type: RollingUpdate\nrollingUpdate:\n  maxSurge: 1\n", false},
+		{Manifest{ApiVersion: "apps/v1", Kind: "Deployment"}, []string{"spec", "strategy"}, "{}", false},
+		{Manifest{ApiVersion: "apps/v1", Kind: "StatefulSet"}, []string{"spec", "replicas"}, "2", false},
+		{Manifest{ApiVersion: "example.com/v1", Kind: "Deployment"}, []string{"spec", "replicas"}, "1", false},
+	}
+
+	for _, test := range tests {
+		var document yaml.Node
+		_ = yaml.Unmarshal([]byte(test.content), &document)
+
+		if isDefaultValue(&test.manifest, test.location, documentRoot(&document)) != test.expected {
+			t.Fatal("Only default values of known fields should be detected.", test.manifest, test.location, test.content)
+		}
+	}
+}
+
+func TestRemoveDefaultValuesRemovesDefaultsOnlySetInOneVersion(t *testing.T) {
+	oldContent := "spec:\n  replicas: 1\n  template:\n    spec:\n      containers:\n      - name: app\n        ports:\n        - containerPort: 80\n          protocol: TCP\n"
+	newContent := "spec:\n  replicas: 3\n  strategy:\n    type: RollingUpdate\n  template:\n    spec:\n      containers:\n      - name: app\n        ports:\n        - containerPort: 80\n"
+	expectedOldContent := "spec:\n  replicas: 1\n  template:\n    spec:\n      containers:\n        - name: app\n          ports:\n            - containerPort: 80\n"
+	expectedNewContent := "spec:\n  replicas: 3\n  template:\n    spec:\n      containers:\n        - name: app\n          ports:\n            - containerPort: 80\n"
+
+	var oldDocument, newDocument yaml.Node
+	_ = yaml.Unmarshal([]byte(oldContent), &oldDocument)
+	_ = yaml.Unmarshal([]byte(newContent), &newDocument)
+
+	changes := removeDefaultValues(&Manifest{ApiVersion: "apps/v1", Kind: "Deployment"}, DefaultMergeKeys, documentRoot(&oldDocument), documentRoot(&newDocument))
+
+	if len(changes) != 2 ||
+		changes[0].String() != "spec.template.spec.containers[name=app].ports[containerPort=80].protocol: TCP → (none)" ||
+		changes[1].String() != `spec.strategy: (none) → {"type":"RollingUpdate"}` {
+		t.Fatal("The removed default values should be returned as field changes.", changes)
+	}
+
+	oldResult, _ := encodeYamlDocument(&oldDocument)
+	newResult, _ := encodeYamlDocument(&newDocument)
+	if oldResult != expectedOldContent || newResult != expectedNewContent {
+		t.Fatal("Only default values set in one version should be removed. Result:\n" + oldResult + "\n" + newResult)
+	}
+}
@@ -0,0 +1,122 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A hunk of a unified diff, consisting of the changed lines and their surrounding context lines.
+// The start lines are one-based; if a hunk contains no lines of a side, the start is the line before the hunk.
+type DiffHunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []DiffLine
+}
+
+// Groups the given diff lines into hunks with the given number of context lines around changes.
+// Changes which are separated by no more than twice the context lines are combined into a single hunk.
+func CreateDiffHunks(lines []DiffLine, contextLines int) []DiffHunk {
+	contextLines = max(contextLines, 0)
+
+	var hunks []DiffHunk
+	oldLine, newLine := 0, 0
+	for start := 0; start < len(lines); {
+		// Find the next change; lines before it only advance the line counters.
+		change := start
+		for change < len(lines) && lines[change].Operation == DiffOperationEqual {
+			change++
+		}
+
+		if change == len(lines) {
+			break
+		}
+
+		// Extend the hunk as long as the next change is within reach of the context.
+		end := change
+		for i := change; i < len(lines); i++ {
+			if lines[i].Operation != DiffOperationEqual {
+				end = i
+				continue
+			}
+
+			if i-end > 2*contextLines {
+				break
+			}
+		}
+
+		hunkStart := max(change-contextLines, start)
+		hunkEnd := min(end+contextLines+1, len(lines))
+
+		for _, line := range lines[start:hunkStart] {
+			oldLine, newLine = advanceLineNumbers(line, oldLine, newLine)
+		}
+
+		hunk := DiffHunk{
+			OldStart: oldLine,
+			NewStart: newLine,
+			Lines:    lines[hunkStart:hunkEnd],
+		}
+
+		for _, line := range hunk.Lines {
+			oldLine, newLine = advanceLineNumbers(line, oldLine, newLine)
+		}
+
+		hunk.OldLines = oldLine - hunk.OldStart
+		hunk.NewLines = newLine - hunk.NewStart
+
+		if hunk.OldLines > 0 {
+			hunk.OldStart++
+		}
+
+		if hunk.NewLines > 0 {
+			hunk.NewStart++
+		}
+
+		hunks = append(hunks, hunk)
+		start = hunkEnd
+	}
+
+	return hunks
+}
+
+// Formats the given diff lines as unified diff with hunk headers and the given number of context lines.
+func FormatUnifiedDiff(lines []DiffLine, contextLines int) string {
+	var sb strings.Builder
+	for i, hunk := range CreateDiffHunks(lines, contextLines) {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString(hunk.Header() + "\n" + formatDiffLines(hunk.Lines))
+	}
+
+	return sb.String()
+}
+
+// Returns the header of the hunk, e.g. '@@ -5,7 +5,8 @@'. Line counts of one are omitted.
+func (h DiffHunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", formatHunkRange(h.OldStart, h.OldLines), formatHunkRange(h.NewStart, h.NewLines))
+}
+
+// Formats the start and line count of one side of a hunk.
+func formatHunkRange(start int, lines int) string {
+	if lines == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, lines)
+}
+
+// Advances the old and new line numbers according to the operation of the given line.
+func advanceLineNumbers(line DiffLine, oldLine int, newLine int) (int, int) {
+	switch line.Operation {
+	case DiffOperationAdded:
+		return oldLine, newLine + 1
+	case DiffOperationRemoved:
+		return oldLine + 1, newLine
+	default:
+		return oldLine + 1, newLine + 1
+	}
+}
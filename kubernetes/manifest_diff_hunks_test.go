@@ -0,0 +1,77 @@
+package kubernetes
+
+import "testing"
+
+func TestFormatUnifiedDiffOnlyContainsContextAroundChanges(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj"
+	new := "a\nb\nc\nd\nE\nf\ng\nh\ni\nj"
+	diff := CreateDiffForManifests(&Manifest{Content: old}, &Manifest{Content: new})
+
+	expectedDiff := `@@ -3,5 +3,5 @@
+ c
+ d
+-e
++E
+ f
+ g`
+
+	if result := FormatUnifiedDiff(diff.Lines, 2); result != expectedDiff {
+		t.Fatal("Unified diff should only contain the change and its context lines. Diff:\n" + result)
+	}
+}
+
+func TestFormatUnifiedDiffSplitsDistantChangesIntoHunks(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj"
+	new := "A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\nk"
+	diff := CreateDiffForManifests(&Manifest{Content: old}, &Manifest{Content: new})
+
+	expectedDiff := `@@ -1,2 +1,2 @@
+-a
++A
+ b
+@@ -9,2 +9,3 @@
+ i
+-j
++J
++k`
+
+	if result := FormatUnifiedDiff(diff.Lines, 1); result != expectedDiff {
+		t.Fatal("Unified diff should contain one hunk per distant change. Diff:\n" + result)
+	}
+}
+
+func TestFormatUnifiedDiffMergesNearbyChangesIntoOneHunk(t *testing.T) {
+	old := "a\nb\nc\nd\ne"
+	new := "A\nb\nc\nd\nE"
+	diff := CreateDiffForManifests(&Manifest{Content: old}, &Manifest{Content: new})
+
+	hunks := CreateDiffHunks(diff.Lines, 2)
+
+	if len(hunks) != 1 || hunks[0].Header() != "@@ -1,5 +1,5 @@" {
+		t.Fatal("Changes separated by no more than twice the context lines should be merged into one hunk.", hunks)
+	}
+}
+
+func TestFormatUnifiedDiffForNewManifest(t *testing.T) {
+	diff := CreateDiffForManifests(&Manifest{}, &Manifest{Content: "a\nb"})
+
+	if result := FormatUnifiedDiff(diff.Lines, 3); result != "@@ -0,0 +1,2 @@\n+a\n+b" {
+		t.Fatal("Unified diff of a new manifest should start at line zero of the old side. Diff:\n" + result)
+	}
+}
+
+func TestFormatUnifiedDiffForRemovedManifest(t *testing.T) {
+	diff := CreateDiffForManifests(&Manifest{Content: "a"}, &Manifest{})
+
+	if result := FormatUnifiedDiff(diff.Lines, 3); result != "@@ -1 +0,0 @@\n-a" {
+		t.Fatal("Unified diff of a removed manifest should start at line zero of the new side. Diff:\n" + result)
+	}
+}
+
+func TestFormatUnifiedDiffOfIdenticalManifestsIsEmpty(t *testing.T) {
+	diff := CreateDiffForManifests(&Manifest{Content: "a\nb"}, &Manifest{Content: "a\nb"})
+
+	if result := FormatUnifiedDiff(diff.Lines, 3); result != "" {
+		t.Fatal("Unified diff of identical manifests should be empty. Diff:\n" + result)
+	}
+}
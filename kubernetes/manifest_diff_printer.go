@@ -3,8 +3,87 @@ package kubernetes
 import (
 	"fmt"
 	"io"
+	"strings"
 )
 
+// ANSI escape sequences used to color terminal output.
+const (
+	colorReset = "\033[0m"
+	colorBold  = "\033[1m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorCyan  = "\033[36m"
+)
+
+// Prints the header line of the diff, optionally in bold for terminals.
+func PrintDiffHeader(diff *ManifestDiff, colored bool, output io.Writer) {
+	fmt.Fprintln(output, colorize(diff.Header(), colorBold, colored))
+}
+
+// Prints the field changes of the diff, one per line, either indented for terminals or as markdown list.
+func PrintFieldChanges(diff *ManifestDiff, formatAsMarkdownList bool, output io.Writer) {
+	for _, change := range diff.FieldChanges {
+		if formatAsMarkdownList {
+			fmt.Fprintln(output, "- `"+change.String()+"`")
+		} else {
+			fmt.Fprintln(output, "  "+change.String())
+		}
+	}
+
+	if formatAsMarkdownList && len(diff.FieldChanges) > 0 {
+		fmt.Fprintln(output)
+	}
+}
+
+// Prints the notes of the diff, e.g. about references with a changed hash suffix, either indented for terminals
+// or as markdown quote.
+func PrintNotes(diff *ManifestDiff, formatAsMarkdownQuote bool, output io.Writer) {
+	for _, note := range diff.Notes {
+		if formatAsMarkdownQuote {
+			fmt.Fprintln(output, "> "+note)
+		} else {
+			fmt.Fprintln(output, "  Note: "+note)
+		}
+	}
+
+	if formatAsMarkdownQuote && len(diff.Notes) > 0 {
+		fmt.Fprintln(output)
+	}
+}
+
+// Prints the resources which only differ in default values with their field changes, either indented for terminals
+// or as markdown list. Nothing is printed if there are no such resources.
+func PrintDefaultsOnlyChanges(changes []DefaultsOnlyChange, formatAsMarkdownList bool, output io.Writer) {
+	if len(changes) == 0 {
+		return
+	}
+
+	if formatAsMarkdownList {
+		fmt.Fprintln(output, "**Resources which only differ in default values:**")
+		fmt.Fprintln(output)
+	} else {
+		fmt.Fprintln(output, "Resources which only differ in default values:")
+	}
+
+	for _, change := range changes {
+		if formatAsMarkdownList {
+			fmt.Fprintln(output, "- "+change.Header())
+			for _, fieldChange := range change.FieldChanges {
+				fmt.Fprintln(output, "  - `"+fieldChange.String()+"`")
+			}
+		} else {
+			fmt.Fprintln(output, change.Header())
+			for _, fieldChange := range change.FieldChanges {
+				fmt.Fprintln(output, "  "+fieldChange.String())
+			}
+		}
+	}
+
+	if formatAsMarkdownList {
+		fmt.Fprintln(output)
+	}
+}
+
 // Creates and prints the diff for two manifests.
 func PrintDiff(diff *ManifestDiff, formatAsMarkdownCodeBlock bool, output io.Writer) {
 	if formatAsMarkdownCodeBlock {
@@ -17,3 +96,25 @@ func PrintDiff(diff *ManifestDiff, formatAsMarkdownCodeBlock bool, output io.Wri
 		fmt.Fprintln(output, "```")
 	}
 }
+
+// Prints the diff for two manifests with ANSI colors for terminals.
+// Added lines are printed green, removed lines red and hunk headers cyan.
+func PrintColoredDiff(diff *ManifestDiff, output io.Writer) {
+	for _, line := range strings.Split(diff.Diff, "\n") {
+		fmt.Fprintln(output, colorizeDiffLine(line))
+	}
+}
+
+// Wraps the given diff line in the ANSI color matching its prefix.
+func colorizeDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "@@"):
+		return colorCyan + line + colorReset
+	case strings.HasPrefix(line, "+"):
+		return colorGreen + line + colorReset
+	case strings.HasPrefix(line, "-"):
+		return colorRed + line + colorReset
+	default:
+		return line
+	}
+}
@@ -36,3 +36,107 @@ func TestPrintDiffWithMarkdownFormatting(t *testing.T) {
 		t.Fatal("Diff should be the content if both manifests are identical. Diff:\n" + output.String())
 	}
 }
+
+func TestPrintColoredDiffColorsAddedRemovedAndHeaderLines(t *testing.T) {
+
+	manifestDiff := ManifestDiff{
+		OldManifest: nil,
+		NewManifest: nil,
+		Diff:        "@@ -1,2 +1,2 @@\n spec:\n-  replicas: 1\n+  replicas: 2",
+	}
+
+	output := new(bytes.Buffer)
+	PrintColoredDiff(&manifestDiff, output)
+
+	expectedOutput := "\033[36m@@ -1,2 +1,2 @@\033[0m\n spec:\n\033[31m-  replicas: 1\033[0m\n\033[32m+  replicas: 2\033[0m\n"
+	if output.String() != expectedOutput {
+		t.Fatalf("Diff should be colored by line prefix. Diff:\n%q", output.String())
+	}
+}
+
+func TestPrintDiffHeaderPrintsHeaderLine(t *testing.T) {
+	manifestDiff := ManifestDiff{
+		ID:           ResourceID{Version: "v1", Kind: "Service", Namespace: "my-namespace", Name: "backend"},
+		ChangeType:   ChangeTypeAdded,
+		LinesAdded:   5,
+		LinesRemoved: 0,
+	}
+
+	output := new(bytes.Buffer)
+	PrintDiffHeader(&manifestDiff, false, output)
+
+	if output.String() != "Service my-namespace/backend (added, +5 -0)\n" {
+		t.Fatal("The header line should be printed. Output:\n" + output.String())
+	}
+
+	output.Reset()
+	PrintDiffHeader(&manifestDiff, true, output)
+
+	if output.String() != "\033[1mService my-namespace/backend (added, +5 -0)\033[0m\n" {
+		t.Fatalf("The colored header line should be printed in bold. Output:\n%q", output.String())
+	}
+}
+
+func TestPrintFieldChangesPrintsIndentedLinesAndMarkdownList(t *testing.T) {
+	diff := ManifestDiff{FieldChanges: []FieldChange{{Path: "spec.replicas", ChangeType: ChangeTypeModified, OldValue: 1, NewValue: 2}}}
+
+	output := new(bytes.Buffer)
+	PrintFieldChanges(&diff, false, output)
+
+	if output.String() != "  spec.replicas: 1 → 2\n" {
+		t.Fatal("Field changes should be printed as indented lines. Output:\n" + output.String())
+	}
+
+	output.Reset()
+	PrintFieldChanges(&diff, true, output)
+
+	if output.String() != "- `spec.replicas: 1 → 2`\n\n" {
+		t.Fatal("Field changes should be printed as markdown list. Output:\n" + output.String())
+	}
+}
+
+func TestPrintDefaultsOnlyChangesPrintsResourcesWithFieldChanges(t *testing.T) {
+	changes := []DefaultsOnlyChange{{
+		ID:           ResourceID{Version: "v1", Kind: "Service", Name: "web"},
+		FieldChanges: []FieldChange{{Path: "spec.type", ChangeType: ChangeTypeAdded, NewValue: "ClusterIP"}},
+	}}
+
+	output := new(bytes.Buffer)
+	PrintDefaultsOnlyChanges(changes, false, output)
+
+	if output.String() != "Resources which only differ in default values:\nService web (defaults only)\n  spec.type: (none) → ClusterIP\n" {
+		t.Fatal("Defaults-only resources should be printed with indented field changes. Output:\n" + output.String())
+	}
+
+	output.Reset()
+	PrintDefaultsOnlyChanges(changes, true, output)
+
+	if output.String() != "**Resources which only differ in default values:**\n\n- Service web (defaults only)\n  - `spec.type: (none) → ClusterIP`\n\n" {
+		t.Fatal("Defaults-only resources should be printed as markdown list. Output:\n" + output.String())
+	}
+
+	output.Reset()
+	PrintDefaultsOnlyChanges(nil, false, output)
+
+	if output.String() != "" {
+		t.Fatal("Nothing should be printed without defaults-only resources. Output:\n" + output.String())
+	}
+}
+
+func TestPrintNotesPrintsIndentedLinesAndMarkdownQuote(t *testing.T) {
+	diff := ManifestDiff{Notes: []string{"Hash suffix of app-config changed: 7h2k9m8t5c → m4t8c2b5h9 (1 reference)"}}
+
+	output := new(bytes.Buffer)
+	PrintNotes(&diff, false, output)
+
+	if output.String() != "  Note: Hash suffix of app-config changed: 7h2k9m8t5c → m4t8c2b5h9 (1 reference)\n" {
+		t.Fatal("Notes should be printed as indented lines. Output:\n" + output.String())
+	}
+
+	output.Reset()
+	PrintNotes(&diff, true, output)
+
+	if output.String() != "> Hash suffix of app-config changed: 7h2k9m8t5c → m4t8c2b5h9 (1 reference)\n\n" {
+		t.Fatal("Notes should be printed as markdown quote. Output:\n" + output.String())
+	}
+}
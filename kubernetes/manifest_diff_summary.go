@@ -0,0 +1,52 @@
+package kubernetes
+
+// Aggregated statistics of a list of diffs.
+type DiffSummary struct {
+	Resources    int
+	Added        int
+	Removed      int
+	Modified     int
+	Renamed      int
+	Reordered    int
+	LinesAdded   int
+	LinesRemoved int
+	FilteredOut  int
+	DefaultsOnly int
+}
+
+// Summarizes the given diffs. Unchanged resources are not counted.
+func SummarizeDiffs(diffs []ManifestDiff) DiffSummary {
+	var summary DiffSummary
+	for _, diff := range diffs {
+		switch diff.ChangeType {
+		case ChangeTypeAdded:
+			summary.Added++
+		case ChangeTypeRemoved:
+			summary.Removed++
+		case ChangeTypeModified:
+			summary.Modified++
+		case ChangeTypeRenamed:
+			summary.Renamed++
+		case ChangeTypeReordered:
+			summary.Reordered++
+		default:
+			continue
+		}
+
+		summary.Resources++
+		summary.LinesAdded += diff.LinesAdded
+		summary.LinesRemoved += diff.LinesRemoved
+	}
+
+	return summary
+}
+
+// Summarizes the diffs of the report, including the number of changed resources which were removed by the filters
+// and the number of resources which only differ in default values.
+func (r *DiffReport) Summary() DiffSummary {
+	summary := SummarizeDiffs(r.Diffs)
+	summary.FilteredOut = r.FilteredOut
+	summary.DefaultsOnly = len(r.DefaultsOnly)
+
+	return summary
+}
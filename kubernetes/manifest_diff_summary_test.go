@@ -0,0 +1,22 @@
+package kubernetes
+
+import "testing"
+
+func TestSummarizeDiffsCountsChangeTypesAndLines(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "v1", Kind: "Service", Name: "backend", Content: "a\nb"}
+	newManifest := Manifest{ApiVersion: "v1", Kind: "Service", Name: "backend", Content: "a\nc\nd"}
+
+	diffs := []ManifestDiff{
+		*CreateDiffForManifests(&oldManifest, &newManifest),
+		*CreateDiffForManifests(&Manifest{}, &newManifest),
+		*CreateDiffForManifests(&oldManifest, &Manifest{}),
+		*CreateDiffForManifests(&oldManifest, &oldManifest),
+	}
+
+	summary := SummarizeDiffs(diffs)
+
+	expectedSummary := DiffSummary{Resources: 3, Added: 1, Removed: 1, Modified: 1, LinesAdded: 5, LinesRemoved: 3}
+	if summary != expectedSummary {
+		t.Fatalf("The summary should count all changed resources and lines. Got: %+v", summary)
+	}
+}
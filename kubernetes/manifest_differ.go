@@ -1,73 +1,387 @@
 package kubernetes
 
 import (
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/kylelemons/godebug/diff"
 )
 
+// The type of change between two manifests.
+type ChangeType string
+
+const (
+	ChangeTypeUnchanged ChangeType = "unchanged"
+	ChangeTypeAdded     ChangeType = "added"
+	ChangeTypeRemoved   ChangeType = "removed"
+	ChangeTypeModified  ChangeType = "modified"
+	ChangeTypeRenamed   ChangeType = "renamed"
+	ChangeTypeReordered ChangeType = "reordered"
+)
+
+// The operation of a single line within a line diff.
+type DiffOperation int
+
+const (
+	DiffOperationEqual DiffOperation = iota
+	DiffOperationAdded
+	DiffOperationRemoved
+)
+
+// A single line of a line diff, consisting of the operation and the line text without prefix.
+type DiffLine struct {
+	Operation DiffOperation
+	Text      string
+}
+
+// The style in which the diff of a manifest is formatted.
+type DiffStyle string
+
+const (
+	DiffStyleFull    DiffStyle = "full"
+	DiffStyleUnified DiffStyle = "unified"
+)
+
+// Parses the given string as diff style. An empty string yields the unified diff style, just like the CLI default.
+func ParseDiffStyle(value string) (DiffStyle, error) {
+	switch DiffStyle(value) {
+	case "", DiffStyleUnified:
+		return DiffStyleUnified, nil
+	case DiffStyleFull:
+		return DiffStyleFull, nil
+	default:
+		return "", errors.New("The diff style '" + value + "' is invalid: must be one of 'full' or 'unified'.")
+	}
+}
+
+// Options which control how the diff of two manifest files is created.
+// Unless the full diff style is set, the diff uses the unified diff style with the given number of context lines.
+// Fields matching one of the ignore rules are removed from all manifests before they are compared.
+// If base64 data is decoded, the values of Secret.data and ConfigMap.binaryData are compared as decoded text.
+// If secrets are redacted, the values of Secret.data and Secret.stringData are replaced by salted fingerprints.
+// For a semantic comparison, manifests are canonicalized, so that only changed values and no formatting changes are part of the diff.
+// Equivalent resource quantities and durations, e.g. '1000m' and '1', are not reported as changes either.
+// For a semantic comparison, items of lists with a merge key are also matched by this key, so that reordered items do
+// not show up in the line diff.
+// Resources whose lists were only reordered are reported as reordered, unless reorders are ignored.
+// If defaults are ignored, fields set to the value the API server would apply anyway count as absent. Resources
+// which only differ in such fields are reported separately as defaults-only changes.
+// Changed resources not matching the filters are not part of the diff.
+// Removed and added resources of the same kind and namespace whose content has at least the rename threshold as
+// similarity (between 0 and 1) are reported as renamed. Renames are not detected if the threshold is 0.
+// If hash suffixes are collapsed, ConfigMaps and Secrets generated by kustomize are matched by their name without hash suffix,
+// and references which only differ in the hash suffix are reported as a note of the referencing resource instead of a line diff.
+type DiffOptions struct {
+	SortOrder            SortOrder
+	DiffStyle            DiffStyle
+	ContextLines         int
+	IgnoreRules          []IgnoreRule
+	DecodeBase64         bool
+	RedactSecrets        bool
+	Semantic             bool
+	MergeKeys            []MergeKey
+	IgnoreReorders       bool
+	IgnoreDefaults       bool
+	Filters              ResourceFilters
+	RenameThreshold      float64
+	CollapseHashSuffixes bool
+}
+
+// The result of diffing two manifest files: the diffs of all changed resources
+// as well as the number of changed resources which were removed by the filters.
+type DiffReport struct {
+	Diffs        []ManifestDiff
+	DefaultsOnly []DefaultsOnlyChange
+	FilteredOut  int
+}
+
+// A resource which only differs in fields with default values, see DiffOptions.IgnoreDefaults.
+// The field changes describe the fields which were only set in one version.
+type DefaultsOnlyChange struct {
+	ID           ResourceID
+	FieldChanges []FieldChange
+}
+
 // The diff between two manifests.
 type ManifestDiff struct {
-	OldManifest *Manifest
-	NewManifest *Manifest
-	Diff        string
+	ID           ResourceID
+	ChangeType   ChangeType
+	OldManifest  *Manifest
+	NewManifest  *Manifest
+	Lines        []DiffLine
+	LinesAdded   int
+	LinesRemoved int
+	Diff         string
+	FieldChanges []FieldChange
+	Notes        []string
 }
 
-// Creates the diff for two manifest files, each containing multiple manifests separated by the YAML separator '---'.
-func CreateDiffForManifestFiles(old *string, new *string) ([]ManifestDiff, error) {
+// Creates the diff for two manifest files, each containing a stream of YAML documents with one manifest each.
+// Without options, the defaults of a zero-value DiffOptions are used.
+func CreateDiffForManifestFiles(old *string, new *string, options *DiffOptions) (*DiffReport, error) {
+	if options == nil {
+		options = &DiffOptions{}
+	}
+
 	// Parse the Kustomizations into individual manifests for easier comparison.
-	oldManifests, err := SplitKustomizationIntoManifests(old)
+	oldManifests, err := SplitKustomizationIntoManifests(strings.NewReader(*old))
 	if err != nil {
 		return nil, err
 	}
 
-	newManifests, err := SplitKustomizationIntoManifests(new)
+	newManifests, err := SplitKustomizationIntoManifests(strings.NewReader(*new))
 	if err != nil {
 		return nil, err
 	}
 
+	// Generated objects are matched by their base name, as their hash suffix changes with their content.
+	if options.CollapseHashSuffixes {
+		keyGeneratedManifestsByBaseName(oldManifests)
+		keyGeneratedManifestsByBaseName(newManifests)
+	}
+
+	// Normalize the manifests, e.g. by removing ignored fields, so that they can be compared.
+	normalization, err := normalizeManifests(oldManifests, newManifests, createManifestTransformations(options), options)
+	if err != nil {
+		return nil, errors.Join(errors.New("Normalizing manifests failed."), err)
+	}
+
 	// Remove all unchanged manifests as we do not need to process them further.
+	allOldManifests, allNewManifests := oldManifests, newManifests
 	oldManifests, newManifests = FilterUnchangedManifests(oldManifests, newManifests)
 
-	// Retrieve all unique manifest hashes and iterate them to create the diff per manifest.
-	manifestHashes := GetUniqueManifestHashes(oldManifests, newManifests)
+	// Manifests whose lists were only reordered are unchanged after normalization, but still need to be reported.
+	reorders := normalization.Reorders
+	if options.IgnoreReorders {
+		clear(reorders)
+	}
+
+	for id := range reorders {
+		(*oldManifests)[id], (*newManifests)[id] = (*allOldManifests)[id], (*allNewManifests)[id]
+	}
+
+	// The same applies to manifests whose only changes are references with a changed hash suffix.
+	notes := normalization.Notes
+	for id := range notes {
+		(*oldManifests)[id], (*newManifests)[id] = (*allOldManifests)[id], (*allNewManifests)[id]
+	}
+
+	// Manifests which only differ in default values are unchanged after normalization as well and are reported separately.
+	var defaultsOnly []DefaultsOnlyChange
+	for id, changes := range normalization.Defaults {
+		_, oldChanged := (*oldManifests)[id]
+		_, newChanged := (*newManifests)[id]
+		if oldChanged || newChanged {
+			continue
+		}
+
+		oldManifest, newManifest := (*allOldManifests)[id], (*allNewManifests)[id]
+		if options.Filters.IsEmpty() || options.Filters.Matches(&oldManifest) || options.Filters.Matches(&newManifest) {
+			defaultsOnly = append(defaultsOnly, DefaultsOnlyChange{ID: newManifest.ID(), FieldChanges: changes})
+		}
+	}
+
+	slices.SortFunc(defaultsOnly, func(a, b DefaultsOnlyChange) int {
+		return compareResourceIDs(a.ID, b.ID)
+	})
+
+	// Remove the changed manifests which do not match the filters, but remember how many there were.
+	filteredOut := FilterManifests(oldManifests, newManifests, &options.Filters)
+
+	// Pair removed and added manifests which were renamed, so that they are diffed with each other.
+	DetectRenamedManifests(oldManifests, newManifests, options.RenameThreshold)
+
+	// Retrieve all unique resource identities and iterate them to create the diff per manifest.
+	resourceIDs := GetUniqueResourceIDs(oldManifests, newManifests)
 
 	var diffs []ManifestDiff
-	for _, hash := range *manifestHashes {
-		oldManifest, newManifest := (*oldManifests)[hash], (*newManifests)[hash]
+	for _, id := range *resourceIDs {
+		oldManifest, newManifest := (*oldManifests)[id], (*newManifests)[id]
 
 		diff := CreateDiffForManifests(&oldManifest, &newManifest)
+		if options.DiffStyle != DiffStyleFull {
+			diff.Diff = FormatUnifiedDiff(diff.Lines, options.ContextLines)
+		}
+
+		diff.FieldChanges, err = CreateFieldChanges(&oldManifest, &newManifest, options.MergeKeys)
+		if err != nil {
+			return nil, errors.Join(errors.New("Creating field changes failed."), err)
+		}
+
+		if len(reorders[id]) > 0 {
+			diff.FieldChanges = append(diff.FieldChanges, reorders[id]...)
+			if diff.ChangeType == ChangeTypeUnchanged {
+				diff.ChangeType = ChangeTypeReordered
+			}
+		}
+
+		// References with a changed hash suffix still modify the resource, e.g. they trigger a rollout.
+		if len(notes[id]) > 0 {
+			diff.Notes = notes[id]
+			if diff.ChangeType == ChangeTypeUnchanged || diff.ChangeType == ChangeTypeReordered {
+				diff.ChangeType = ChangeTypeModified
+			}
+		}
+
 		diffs = append(diffs, *diff)
 	}
 
-	return diffs, nil
+	// Sort the diffs, as the order of the unique resource identities is not deterministic.
+	SortManifestDiffs(diffs, options.SortOrder)
+
+	return &DiffReport{Diffs: diffs, DefaultsOnly: defaultsOnly, FilteredOut: filteredOut}, nil
 }
 
 // Creates the diff for two manifests.
+// A manifest with empty content is treated as not existing, i.e. the other manifest has been added or removed.
 func CreateDiffForManifests(old *Manifest, new *Manifest) *ManifestDiff {
+	// The identity of the new manifest is used, unless the manifest has been removed.
+	id := new.ID()
+	if new.Content == "" {
+		id = old.ID()
+	}
 
 	if old.Content == new.Content {
 		return &ManifestDiff{
+			ID:          id,
+			ChangeType:  ChangeTypeUnchanged,
 			OldManifest: old,
 			NewManifest: new,
+			Lines:       createDiffLines(splitIntoLines(old.Content), splitIntoLines(new.Content)),
 			Diff:        old.Content,
 		}
 	}
 
-	diff := diff.Diff(old.Content, new.Content)
+	lines := createDiffLines(splitIntoLines(old.Content), splitIntoLines(new.Content))
 
-	// In case a new manifest is given, the old content is diffed as empty line, which we remove.
-	diff = strings.TrimPrefix(diff, "-\n")
+	linesAdded, linesRemoved := 0, 0
+	for _, line := range lines {
+		switch line.Operation {
+		case DiffOperationAdded:
+			linesAdded++
+		case DiffOperationRemoved:
+			linesRemoved++
+		}
+	}
 
-	// The diff may contain an empty line at the end, which we remove.
-	diff = strings.TrimSuffix(diff, "\n ")
+	return &ManifestDiff{
+		ID:           id,
+		ChangeType:   determineChangeType(old, new),
+		OldManifest:  old,
+		NewManifest:  new,
+		Lines:        lines,
+		LinesAdded:   linesAdded,
+		LinesRemoved: linesRemoved,
+		Diff:         formatDiffLines(lines),
+	}
+}
 
-	// In case a removed manifest is given, the new content is diffed as empty line, which we remove.
-	diff = strings.TrimSuffix(diff, "\n+")
+// Returns a readable header for the diff, e.g. 'Deployment my-namespace/my-app (modified, +3 -2)'.
+func (d *ManifestDiff) Header() string {
+	changeType := string(d.ChangeType)
+	if d.ChangeType == ChangeTypeRenamed {
+		changeType += " from " + d.OldManifest.ID().NamespacedName()
+	}
 
-	return &ManifestDiff{
-		OldManifest: old,
-		NewManifest: new,
-		Diff:        diff,
+	return fmt.Sprintf("%s %s (%s, +%d -%d)", d.ID.Kind, d.ID.NamespacedName(), changeType, d.LinesAdded, d.LinesRemoved)
+}
+
+// Determines whether the diff contains changed lines. This is not the case for resources whose lists were only
+// reordered or whose only changes are references with a changed hash suffix.
+func (d *ManifestDiff) HasChangedLines() bool {
+	return d.LinesAdded > 0 || d.LinesRemoved > 0
+}
+
+// Returns the header line of the change, e.g. 'Service my-namespace/web (defaults only)'.
+func (c *DefaultsOnlyChange) Header() string {
+	return c.ID.Kind + " " + c.ID.NamespacedName() + " (defaults only)"
+}
+
+// Determines the type of change between two differing manifests.
+func determineChangeType(old *Manifest, new *Manifest) ChangeType {
+	switch {
+	case old.Content == "":
+		return ChangeTypeAdded
+	case new.Content == "":
+		return ChangeTypeRemoved
+	case old.Key().Name != new.Key().Name:
+		return ChangeTypeRenamed
+	default:
+		return ChangeTypeModified
+	}
+}
+
+// Splits the given content into lines. Trailing line breaks are ignored and empty content yields no lines.
+func splitIntoLines(content string) []string {
+	content = strings.TrimRight(content, "\n")
+	if content == "" {
+		return nil
+	}
+
+	return strings.Split(content, "\n")
+}
+
+// Creates the line diff for the given old and new lines.
+// Within a block of changes, all removed lines are listed before the added lines.
+func createDiffLines(old []string, new []string) []DiffLine {
+	if slices.Equal(old, new) {
+		lines := make([]DiffLine, 0, len(old))
+		for _, line := range old {
+			lines = append(lines, DiffLine{Operation: DiffOperationEqual, Text: line})
+		}
+
+		return lines
+	}
+
+	var lines, added []DiffLine
+	for _, chunk := range diff.DiffChunks(old, new) {
+		for _, line := range chunk.Deleted {
+			lines = append(lines, DiffLine{Operation: DiffOperationRemoved, Text: line})
+		}
+
+		for _, line := range chunk.Added {
+			added = append(added, DiffLine{Operation: DiffOperationAdded, Text: line})
+		}
+
+		if len(chunk.Equal) == 0 {
+			continue
+		}
+
+		lines = append(lines, added...)
+		added = nil
+
+		for _, line := range chunk.Equal {
+			lines = append(lines, DiffLine{Operation: DiffOperationEqual, Text: line})
+		}
+	}
+
+	return append(lines, added...)
+}
+
+// Formats the given diff lines with a '+', '-' or ' ' prefix per line.
+func formatDiffLines(lines []DiffLine) string {
+	var sb strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString(line.Operation.Prefix() + line.Text)
+	}
+
+	return sb.String()
+}
+
+// Returns the prefix used for lines with the diff operation in a textual diff.
+func (o DiffOperation) Prefix() string {
+	switch o {
+	case DiffOperationAdded:
+		return "+"
+	case DiffOperationRemoved:
+		return "-"
+	default:
+		return " "
 	}
 }
@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -18,12 +19,14 @@ func TestCreateDiffForManifestFilesReturnsCorrectResult(t *testing.T) {
 		"---\n" +
 		"apiVersion: v1\nkind: Service\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  type: NodePort\n  sessionAffinity: |\n    -----BEGIN CERTIFICATE-----\n    MIIF6TCCA8WgAwIBAgIUClmW\n    -----END CERTIFICATE-----"
 
-	diffs, err := CreateDiffForManifestFiles(&oldManifest, &newManifest)
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{DiffStyle: DiffStyleFull})
 
 	if err != nil {
 		t.Fatal("Diffing manifests should not fail", err)
 	}
 
+	diffs := report.Diffs
+
 	if len(diffs) != 2 {
 		t.Fatal("Diff of files should contain diff of all changed manifests", diffs)
 	}
@@ -162,3 +165,480 @@ func diffsContainExpectedDiff(diffs []ManifestDiff, expectedDiff string) bool {
 
 	return false
 }
+
+func TestCreateDiffForManifestFilesTreatsApiVersionBumpAsModification(t *testing.T) {
+	oldManifest := "apiVersion: autoscaling/v2beta2\nkind: HorizontalPodAutoscaler\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  maxReplicas: 3"
+	newManifest := "apiVersion: autoscaling/v2\nkind: HorizontalPodAutoscaler\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  maxReplicas: 3"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{DiffStyle: DiffStyleFull})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	diffs := report.Diffs
+
+	if len(diffs) != 1 {
+		t.Fatal("An apiVersion bump should yield a single diff", diffs)
+	}
+
+	expectedDiff := `-apiVersion: autoscaling/v2beta2
++apiVersion: autoscaling/v2
+ kind: HorizontalPodAutoscaler
+ metadata:
+   name: backend
+   namespace: my-namespace
+ spec:
+   maxReplicas: 3`
+
+	if diffs[0].Diff != expectedDiff {
+		t.Fatal("Diff should show the changed apiVersion line. Diff:\n" + diffs[0].Diff)
+	}
+
+	if diffs[0].ID.ApiVersion() != "autoscaling/v2" {
+		t.Fatal("Diff should be identified by the new apiVersion.")
+	}
+}
+
+func TestCreateDiffForManifestsDeterminesChangeTypeAndLineCounts(t *testing.T) {
+	oldManifest := Manifest{
+		ApiVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       "backend",
+		Namespace:  "my-namespace",
+		Content:    "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  replicas: 1\n  paused: true\n",
+	}
+	newManifest := Manifest{
+		ApiVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       "backend",
+		Namespace:  "my-namespace",
+		Content:    "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  replicas: 2\n  minReadySeconds: 5\n  revisionHistoryLimit: 3\n",
+	}
+
+	diff := CreateDiffForManifests(&oldManifest, &newManifest)
+	if diff.ChangeType != ChangeTypeModified || diff.LinesAdded != 3 || diff.LinesRemoved != 2 {
+		t.Fatalf("Diff of altered manifest should be a modification with +3 -2 lines. Got: %s +%d -%d", diff.ChangeType, diff.LinesAdded, diff.LinesRemoved)
+	}
+
+	diff = CreateDiffForManifests(&Manifest{}, &newManifest)
+	if diff.ChangeType != ChangeTypeAdded || diff.LinesAdded != 9 || diff.LinesRemoved != 0 {
+		t.Fatalf("Diff of new manifest should be an addition with +9 -0 lines. Got: %s +%d -%d", diff.ChangeType, diff.LinesAdded, diff.LinesRemoved)
+	}
+
+	diff = CreateDiffForManifests(&oldManifest, &Manifest{})
+	if diff.ChangeType != ChangeTypeRemoved || diff.LinesAdded != 0 || diff.LinesRemoved != 8 {
+		t.Fatalf("Diff of removed manifest should be a removal with +0 -8 lines. Got: %s +%d -%d", diff.ChangeType, diff.LinesAdded, diff.LinesRemoved)
+	}
+
+	diff = CreateDiffForManifests(&oldManifest, &oldManifest)
+	if diff.ChangeType != ChangeTypeUnchanged || diff.LinesAdded != 0 || diff.LinesRemoved != 0 {
+		t.Fatalf("Diff of identical manifests should be unchanged with +0 -0 lines. Got: %s +%d -%d", diff.ChangeType, diff.LinesAdded, diff.LinesRemoved)
+	}
+}
+
+func TestCreateDiffForManifestsDetectsRenamedManifests(t *testing.T) {
+	oldManifest := Manifest{
+		ApiVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       "config-old",
+		Content:    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config-old\ndata:\n  foo: bar",
+	}
+	newManifest := Manifest{
+		ApiVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       "config-new",
+		Content:    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config-new\ndata:\n  foo: bar",
+	}
+
+	diff := CreateDiffForManifests(&oldManifest, &newManifest)
+
+	if diff.ChangeType != ChangeTypeRenamed || diff.ID.Name != "config-new" {
+		t.Fatal("Diff of manifests with different names should be a rename identified by the new name.")
+	}
+}
+
+func TestCreateDiffForManifestsIgnoresTrailingLineBreaks(t *testing.T) {
+	oldManifest := Manifest{Content: "kind: Service\nspec:\n  type: ClusterIP\n\n"}
+	newManifest := Manifest{Content: "kind: Service\nspec:\n  type: NodePort\n"}
+
+	diff := CreateDiffForManifests(&oldManifest, &newManifest)
+
+	if diff.Diff != " kind: Service\n spec:\n-  type: ClusterIP\n+  type: NodePort" {
+		t.Fatal("Diff should not contain trailing empty lines. Diff:\n" + diff.Diff)
+	}
+}
+
+func TestCreateDiffForManifestFilesUsesUnifiedDiffStyle(t *testing.T) {
+	oldManifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  replicas: 1\n  paused: false\n"
+	newManifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: backend\n  namespace: my-namespace\nspec:\n  replicas: 2\n  paused: false\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{DiffStyle: DiffStyleUnified, ContextLines: 1})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	diffs := report.Diffs
+
+	expectedDiff := `@@ -6,3 +6,3 @@
+ spec:
+-  replicas: 1
++  replicas: 2
+   paused: false`
+
+	if len(diffs) != 1 || diffs[0].Diff != expectedDiff {
+		t.Fatal("Diff should be formatted as unified diff.", diffs)
+	}
+}
+
+func TestManifestDiffHeaderContainsIdentityChangeTypeAndLineCounts(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "my-app", Namespace: "my-namespace", Content: "a\nb\nc"}
+	newManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "my-app", Namespace: "my-namespace", Content: "a\nB\nC\nD"}
+
+	diff := CreateDiffForManifests(&oldManifest, &newManifest)
+	if diff.Header() != "Deployment my-namespace/my-app (modified, +3 -2)" {
+		t.Fatal("The header of a modified manifest should contain identity, change type and line counts. Got: " + diff.Header())
+	}
+
+	renamedManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "my-new-app", Namespace: "my-namespace", Content: "a\nb\nd"}
+
+	diff = CreateDiffForManifests(&oldManifest, &renamedManifest)
+	if diff.Header() != "Deployment my-namespace/my-new-app (renamed from my-namespace/my-app, +1 -1)" {
+		t.Fatal("The header of a renamed manifest should contain the old identity. Got: " + diff.Header())
+	}
+}
+
+func TestCreateDiffForManifestFilesDropsManifestsWithOnlyIgnoredChanges(t *testing.T) {
+	oldManifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: backend\n  annotations:\n    checksum/config: abc\nspec:\n  replicas: 1\n"
+	newManifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: backend\n  annotations:\n    checksum/config: def\nspec:\n  replicas: 1\n"
+
+	rule, _ := ParseIgnoreRule(`metadata.annotations["checksum/config"]`)
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{IgnoreRules: []IgnoreRule{rule}})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	diffs := report.Diffs
+
+	if len(diffs) != 0 {
+		t.Fatal("Manifests whose only differences are ignored fields should not be part of the diff.", diffs)
+	}
+}
+
+func TestCreateDiffForManifestFilesCountsFilteredOutResources(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\ndata:\n  x: \"1\"\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: b\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: unchanged\n"
+	newManifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\ndata:\n  x: \"2\"\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: unchanged\n"
+
+	filter, _ := ParseResourceFilter("kind=Secret")
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{Filters: ResourceFilters{Exclude: []ResourceFilter{filter}}})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 1 || report.Diffs[0].ID.Kind != "ConfigMap" {
+		t.Fatal("Only resources matching the filters should be part of the diff.", report.Diffs)
+	}
+
+	if report.FilteredOut != 1 {
+		t.Fatal("Only changed resources removed by the filters should be counted.", report.FilteredOut)
+	}
+}
+
+func TestCreateDiffForManifestFilesComparesSemantically(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n  labels: {app: web}\nspec:\n  ports:\n  - port: 80\n    name: http\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\ndata:\n  port: 80\n"
+	newManifest := "kind: Service\napiVersion: v1\nmetadata:\n  labels:\n    app: \"web\"\n  name: web\nspec:\n  ports:\n    - name: 'http'\n      port: 80\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\ndata:\n  port: \"81\"\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{DiffStyle: DiffStyleFull, Semantic: true})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 1 || report.Diffs[0].ID.Kind != "ConfigMap" {
+		t.Fatal("Only resources with changed values should be part of the diff.", report.Diffs)
+	}
+
+	expectedDiff := " apiVersion: v1\n data:\n-  port: \"80\"\n+  port: \"81\"\n kind: ConfigMap\n metadata:\n   name: cfg"
+	if report.Diffs[0].Diff != expectedDiff {
+		t.Fatal("The diff should be created from the canonical manifests. Diff:\n" + report.Diffs[0].Diff)
+	}
+}
+
+func TestCreateDiffForManifestFilesCanonicalizesEmbeddedFiles(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\ndata:\n  config.json: '{\"server\": {\"port\": 80, \"host\": \"a\"}, \"debug\": false}'\n"
+	newManifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\ndata:\n  config.json: |\n    {\n        \"debug\": false,\n        \"server\": {\"host\": \"a\", \"port\": 81}\n    }\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{DiffStyle: DiffStyleFull, Semantic: true})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 1 {
+		t.Fatal("The changed ConfigMap should be part of the diff.", report.Diffs)
+	}
+
+	expectedDiff := " apiVersion: v1\n data:\n   config.json: |\n     {\n       \"debug\": false,\n       \"server\": {\n         \"host\": \"a\",\n-        \"port\": 80\n+        \"port\": 81\n       }\n     }\n kind: ConfigMap\n metadata:\n   name: cfg"
+	if report.Diffs[0].Diff != expectedDiff {
+		t.Fatal("Only the changed key of the embedded file should be part of the diff. Diff:\n" + report.Diffs[0].Diff)
+	}
+
+	reformattedManifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\ndata:\n  config.json: |\n    {\"debug\": false, \"server\": {\"host\": \"a\",\n      \"port\": 80}}\n"
+
+	report, err = CreateDiffForManifestFiles(&oldManifest, &reformattedManifest, &DiffOptions{DiffStyle: DiffStyleFull, Semantic: true})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 0 {
+		t.Fatal("Embedded files which are only formatted differently should not be part of the diff.", report.Diffs)
+	}
+}
+
+func TestCreateDiffForManifestFilesIgnoresEquivalentQuantities(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n  - name: app\n    resources:\n      requests:\n        cpu: 1000m\n        memory: 1Gi\n"
+	newManifest := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n  - name: app\n    resources:\n      requests:\n        cpu: 1\n        memory: 1024Mi\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{Semantic: true})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 0 {
+		t.Fatal("Resources with equivalent quantities should not be reported as changed.", report.Diffs)
+	}
+
+	report, err = CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 1 {
+		t.Fatal("Equivalent quantities should only be ignored for semantic comparisons.", report.Diffs)
+	}
+}
+
+func TestCreateDiffForManifestFilesReportsDefaultsOnlyResourcesSeparately(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports:\n  - port: 80\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\nspec:\n  template:\n    spec:\n      containers:\n      - name: app\n        image: app:1\n"
+	newManifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports:\n  - port: 80\n    protocol: TCP\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\nspec:\n  replicas: 1\n  template:\n    spec:\n      containers:\n      - name: app\n        image: app:2\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{IgnoreDefaults: true, MergeKeys: DefaultMergeKeys})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 1 || report.Diffs[0].ID.Kind != "Deployment" || strings.Contains(report.Diffs[0].Diff, "replicas") {
+		t.Fatal("Only resources with real changes should be diffed, without their default values.", report.Diffs)
+	}
+
+	if len(report.DefaultsOnly) != 1 || report.DefaultsOnly[0].ID.Kind != "Service" || report.DefaultsOnly[0].FieldChanges[0].Path != "spec.ports[port=80].protocol" {
+		t.Fatal("Resources which only differ in default values should be reported separately.", report.DefaultsOnly)
+	}
+
+	report, err = CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 2 || len(report.DefaultsOnly) != 0 {
+		t.Fatal("Default values should only be ignored if enabled.", report.Diffs, report.DefaultsOnly)
+	}
+}
+
+func TestCreateDiffForManifestFilesReportsRenamedResources(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config-7h2k9\ndata:\n  a: \"1\"\n  b: \"2\"\n  c: \"3\"\n"
+	newManifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config-m4t8c\ndata:\n  a: \"1\"\n  b: \"2\"\n  c: \"4\"\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{RenameThreshold: 0.5})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 1 || report.Diffs[0].ChangeType != ChangeTypeRenamed || report.Diffs[0].Header() != "ConfigMap app-config-m4t8c (renamed from app-config-7h2k9, +2 -2)" {
+		t.Fatal("Similar removed and added resources should be reported as renamed.", report.Diffs)
+	}
+
+	report, err = CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 2 {
+		t.Fatal("Renames should not be detected without threshold.", report.Diffs)
+	}
+}
+
+func TestCreateDiffForManifestFilesCollapsesHashSuffixes(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config-7h2k9m8t5c\ndata:\n  level: info\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\nspec:\n  template:\n    spec:\n      volumes:\n      - name: config\n        configMap:\n          name: app-config-7h2k9m8t5c\n"
+	newManifest := strings.NewReplacer("7h2k9m8t5c", "m4t8c2b5h9", "level: info", "level: debug").Replace(oldManifest)
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{CollapseHashSuffixes: true})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 2 || report.Diffs[0].ChangeType != ChangeTypeRenamed || report.Diffs[0].ID.Name != "app-config-m4t8c2b5h9" {
+		t.Fatal("Generated objects should be matched by their base name.", report.Diffs)
+	}
+
+	consumer := report.Diffs[1]
+	if consumer.ChangeType != ChangeTypeModified || consumer.HasChangedLines() || len(consumer.Notes) != 1 {
+		t.Fatal("References which only changed their hash suffix should be collapsed into a note.", consumer)
+	}
+
+	report, err = CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 3 || !report.Diffs[0].HasChangedLines() || len(report.Diffs[0].Notes) != 0 {
+		t.Fatal("Hash suffixes should only be collapsed if enabled.", report.Diffs)
+	}
+}
+
+func TestCreateDiffForManifestFilesReportsReorderedLists(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports:\n  - port: 80\n  - port: 443\n"
+	newManifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports:\n  - port: 443\n  - port: 80\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{Semantic: true, MergeKeys: DefaultMergeKeys})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 1 || report.Diffs[0].ChangeType != ChangeTypeReordered || report.Diffs[0].LinesAdded != 0 || report.Diffs[0].LinesRemoved != 0 {
+		t.Fatal("Resources whose lists were only reordered should be reported as reordered without line changes.", report.Diffs)
+	}
+
+	if len(report.Diffs[0].FieldChanges) != 1 || report.Diffs[0].FieldChanges[0].Path != "spec.ports" {
+		t.Fatal("The reordered list should be part of the field changes.", report.Diffs[0].FieldChanges)
+	}
+
+	report, err = CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{Semantic: true, MergeKeys: DefaultMergeKeys, IgnoreReorders: true})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 0 {
+		t.Fatal("Reordered resources should not be reported if reorders are ignored.", report.Diffs)
+	}
+}
+
+func TestCreateDiffForManifestFilesRedactsSecrets(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: creds\ndata:\n  user: YWRtaW4=\n  password: c2VjcmV0\n"
+	newManifest := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: creds\ndata:\n  user: YWRtaW4=\n  password: c2VjcmV0Mg==\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{RedactSecrets: true})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	diff := report.Diffs[0]
+	if strings.Contains(diff.Diff, "c2VjcmV0") || strings.Contains(diff.NewManifest.Content, "YWRtaW4=") {
+		t.Fatal("Secret values should not be part of the diff.", diff.Diff)
+	}
+
+	if diff.LinesAdded != 1 || diff.LinesRemoved != 1 || !strings.Contains(diff.Diff, "+  password: redacted:") {
+		t.Fatal("Changed secret values should still be visible as changed fingerprints.", diff.Diff)
+	}
+}
+
+func TestCreateDiffForManifestFilesKeepsSourceContentOfSemanticComparison(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: token\ndata:\n  token: c2VjcmV0\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  type: ClusterIP\n  ports:\n  - port: 80\n"
+	newManifest := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: token\ndata:\n  token: b3RoZXI=\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  type: ClusterIP\n  ports:\n  - port: 81\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{Semantic: true, RedactSecrets: true})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 2 {
+		t.Fatal("Both resources should be part of the diff.", report.Diffs)
+	}
+
+	secret, service := report.Diffs[0], report.Diffs[1]
+	if service.NewManifest.Source() != "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  type: ClusterIP\n  ports:\n  - port: 81\n" {
+		t.Fatal("The source content should keep the original key order. Content:\n" + service.NewManifest.Source())
+	}
+
+	if strings.Contains(secret.OldManifest.Source(), "c2VjcmV0") || !strings.Contains(secret.OldManifest.Source(), "redacted:") {
+		t.Fatal("The source content should contain redacted secrets. Content:\n" + secret.OldManifest.Source())
+	}
+}
+
+func TestCreateDiffForManifestFilesWithoutOptionsUsesDefaults(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  type: ClusterIP\n"
+	newManifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  type: NodePort\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, nil)
+
+	if err != nil {
+		t.Fatal("Diffing manifests without options should not fail", err)
+	}
+
+	if len(report.Diffs) != 1 || report.Diffs[0].ChangeType != ChangeTypeModified {
+		t.Fatal("Diffing manifests without options should use the default options.", report.Diffs)
+	}
+
+	expectedDiff := "@@ -6 +6 @@\n-  type: ClusterIP\n+  type: NodePort"
+	if report.Diffs[0].Diff != expectedDiff {
+		t.Fatal("Diffing manifests without options should use the unified diff style. Diff:\n" + report.Diffs[0].Diff)
+	}
+}
+
+func TestParseDiffStyleDefaultsToUnified(t *testing.T) {
+	for value, expected := range map[string]DiffStyle{"": DiffStyleUnified, "unified": DiffStyleUnified, "full": DiffStyleFull} {
+		if style, err := ParseDiffStyle(value); err != nil || style != expected {
+			t.Fatal("The diff style '"+value+"' should be parsed as '"+string(expected)+"'.", style, err)
+		}
+	}
+
+	if _, err := ParseDiffStyle("split"); err == nil {
+		t.Fatal("Invalid diff styles should not be parsed successfully.")
+	}
+}
+
+func TestCreateDiffForManifestFilesReportsSmallQuantityChanges(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n  - name: app\n    resources:\n      requests:\n        cpu: 100m\n      limits:\n        cpu: 250m\n"
+	newManifest := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n  - name: app\n    resources:\n      requests:\n        cpu: 125m\n      limits:\n        cpu: 300m\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{Semantic: true})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 1 || report.Diffs[0].LinesAdded != 2 || report.Diffs[0].LinesRemoved != 2 {
+		t.Fatal("Quantities which differ only slightly should be reported as changed.", report.Diffs)
+	}
+}
+
+func TestCreateDiffForManifestFilesKeepsListOrderOfRawComparison(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports:\n  - port: 80\n  - port: 443\n"
+	newManifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports:\n  - port: 443\n  - port: 80\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{MergeKeys: DefaultMergeKeys})
+
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	if len(report.Diffs) != 1 || report.Diffs[0].ChangeType != ChangeTypeModified || report.Diffs[0].OldManifest.Content != oldManifest {
+		t.Fatal("Lists should not be aligned by their merge keys without a semantic comparison.", report.Diffs)
+	}
+}
@@ -0,0 +1,533 @@
+package kubernetes
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The format of a file embedded in a ConfigMap.
+type embeddedFileFormat string
+
+const (
+	embeddedFileFormatNone       embeddedFileFormat = ""
+	embeddedFileFormatYaml       embeddedFileFormat = "yaml"
+	embeddedFileFormatJson       embeddedFileFormat = "json"
+	embeddedFileFormatToml       embeddedFileFormat = "toml"
+	embeddedFileFormatProperties embeddedFileFormat = "properties"
+)
+
+// Detects the format of the file embedded in a ConfigMap under the given key, based on the extension of the key
+// and, if the extension is unknown, the content. Only JSON and multi-line YAML mappings are detected by content.
+func detectEmbeddedFileFormat(key string, content string) embeddedFileFormat {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".yaml", ".yml":
+		return embeddedFileFormatYaml
+	case ".json":
+		return embeddedFileFormatJson
+	case ".toml":
+		return embeddedFileFormatToml
+	case ".properties":
+		return embeddedFileFormatProperties
+	}
+
+	trimmed := strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "["):
+		return embeddedFileFormatJson
+	case strings.Contains(trimmed, "\n") && strings.Contains(trimmed, ":"):
+		var document yaml.Node
+		if yaml.Unmarshal([]byte(content), &document) == nil && documentRoot(&document) != nil && documentRoot(&document).Kind == yaml.MappingNode {
+			return embeddedFileFormatYaml
+		}
+	}
+
+	return embeddedFileFormatNone
+}
+
+// Determines whether the given location of the given manifest contains a file embedded in the data of a ConfigMap.
+func isEmbeddedFileLocation(manifest *Manifest, location []string) bool {
+	group, _ := SplitApiVersion(manifest.ApiVersion)
+
+	return manifest.Kind == "ConfigMap" && group == "" && len(location) == 2 && location[0] == "data"
+}
+
+// Canonicalizes the file embedded in the data of a ConfigMap under the given key, so that files which only differ in
+// formatting, key order or comments are encoded identically: JSON is indented with sorted keys, YAML is canonicalized
+// like manifests, TOML is written with sorted keys and tables and properties are sorted by their key.
+// Returns false if the content is not an embedded file, cannot be parsed or only contains a scalar.
+func canonicalizeEmbeddedFile(key string, content string) (string, bool) {
+	format := detectEmbeddedFileFormat(key, content)
+
+	root, err := parseEmbeddedFile(format, content)
+	if err != nil || !isCollectionNode(root) {
+		return "", false
+	}
+
+	switch format {
+	case embeddedFileFormatJson:
+		return canonicalizeJsonFile(content)
+	case embeddedFileFormatYaml:
+		canonicalizeNode(&Manifest{}, root, nil)
+
+		result, err := encodeYamlDocument(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}})
+
+		return result, err == nil
+	case embeddedFileFormatToml:
+		var builder strings.Builder
+		formatTomlTable(&builder, root, nil)
+
+		return strings.TrimLeft(builder.String(), "\n"), true
+	case embeddedFileFormatProperties:
+		return canonicalizePropertiesFile(content), true
+	default:
+		return "", false
+	}
+}
+
+// Indents the given JSON file with sorted keys. Numbers are kept as they are, so that large numbers do not lose precision.
+func canonicalizeJsonFile(content string) (string, bool) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return "", false
+	}
+
+	return buffer.String(), true
+}
+
+// Writes the given properties file as 'key=value' lines sorted by key. Later entries replace earlier ones with the same key.
+func canonicalizePropertiesFile(content string) string {
+	keys, values := parsePropertiesEntries(content)
+
+	entries := make(map[string]string)
+	for i, key := range keys {
+		entries[key] = values[i]
+	}
+
+	var builder strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(entries)) {
+		builder.WriteString(key + "=" + entries[key] + "\n")
+	}
+
+	return builder.String()
+}
+
+// Keys of TOML tables which can be written without quotes.
+var bareTomlKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Writes the given TOML table with the given keys: first the key-value pairs sorted by key, then the sub-tables and
+// arrays of tables with their headers, also sorted by key.
+func formatTomlTable(builder *strings.Builder, table *yaml.Node, keys []string) {
+	type entry struct {
+		Key   string
+		Value *yaml.Node
+	}
+
+	var values, tables []entry
+	for i := 0; i+1 < len(table.Content); i += 2 {
+		key, value := table.Content[i].Value, table.Content[i+1]
+		if value.Kind == yaml.MappingNode || isTomlArrayOfTables(value) {
+			tables = append(tables, entry{key, value})
+		} else {
+			values = append(values, entry{key, value})
+		}
+	}
+
+	compareEntries := func(a, b entry) int { return cmp.Compare(a.Key, b.Key) }
+	slices.SortFunc(values, compareEntries)
+	slices.SortFunc(tables, compareEntries)
+
+	for _, value := range values {
+		builder.WriteString(formatTomlKey(value.Key) + " = " + formatTomlValue(value.Value) + "\n")
+	}
+
+	for _, table := range tables {
+		tableKeys := append(slices.Clip(keys), table.Key)
+		header := strings.Join(slices.Collect(func(yield func(string) bool) {
+			for _, key := range tableKeys {
+				if !yield(formatTomlKey(key)) {
+					return
+				}
+			}
+		}), ".")
+
+		if table.Value.Kind == yaml.MappingNode {
+			builder.WriteString("\n[" + header + "]\n")
+			formatTomlTable(builder, table.Value, tableKeys)
+
+			continue
+		}
+
+		for _, item := range table.Value.Content {
+			builder.WriteString("\n[[" + header + "]]\n")
+			formatTomlTable(builder, item, tableKeys)
+		}
+	}
+}
+
+// Determines whether the given node is a TOML array of tables, i.e. a non-empty sequence of mappings.
+func isTomlArrayOfTables(node *yaml.Node) bool {
+	return node.Kind == yaml.SequenceNode && len(node.Content) > 0 &&
+		!slices.ContainsFunc(node.Content, func(item *yaml.Node) bool { return item.Kind != yaml.MappingNode })
+}
+
+// Formats the given TOML key, quoting it if necessary.
+func formatTomlKey(key string) string {
+	if bareTomlKeyPattern.MatchString(key) {
+		return key
+	}
+
+	return strconv.Quote(key)
+}
+
+// Formats the given TOML value in its canonical form, e.g. strings with double quotes and arrays on a single line.
+func formatTomlValue(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			items = append(items, formatTomlValue(item))
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+	case yaml.MappingNode:
+		pairs := make([]string, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			pairs = append(pairs, formatTomlKey(node.Content[i].Value)+" = "+formatTomlValue(node.Content[i+1]))
+		}
+
+		slices.Sort(pairs)
+
+		return "{ " + strings.Join(pairs, ", ") + " }"
+	default:
+		if node.ShortTag() == "!!str" {
+			return strconv.Quote(node.Value)
+		}
+
+		canonicalizeScalar(node)
+
+		return node.Value
+	}
+}
+
+// Parses the given embedded file in the given format into a YAML node, so that it can be compared structurally.
+func parseEmbeddedFile(format embeddedFileFormat, content string) (*yaml.Node, error) {
+	switch format {
+	case embeddedFileFormatYaml, embeddedFileFormatJson:
+		// JSON is a subset of YAML, so both can be parsed the same way.
+		document, err := parseSingleYamlDocument(content)
+		if err != nil {
+			return nil, err
+		}
+
+		return documentRoot(document), nil
+	case embeddedFileFormatToml:
+		return parseTomlFile(content)
+	case embeddedFileFormatProperties:
+		return parsePropertiesFile(content), nil
+	default:
+		return nil, errors.New("The format of the embedded file is unknown.")
+	}
+}
+
+// Parses the given content as a single YAML document. Files with multiple documents are rejected, as only the first
+// document would be compared otherwise.
+func parseSingleYamlDocument(content string) (*yaml.Node, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+
+	var document yaml.Node
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	if documentRoot(&document) == nil {
+		return nil, errors.New("The embedded file is empty.")
+	}
+
+	var next yaml.Node
+	if err := decoder.Decode(&next); !errors.Is(err, io.EOF) {
+		return nil, errors.New("Embedded files with multiple documents are not supported.")
+	}
+
+	return &document, nil
+}
+
+// Parses the given TOML file into a YAML mapping. Only a subset of TOML is supported: tables, arrays of tables and
+// key-value pairs with single-line values. Inline tables and multi-line strings are not supported.
+func parseTomlFile(content string) (*yaml.Node, error) {
+	root := newMappingNode()
+	table := root
+
+	for number, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[["):
+			header, err := cutTomlHeader(line[2:], "]]")
+			if err != nil {
+				return nil, errors.Join(errors.New("Invalid TOML table header in line "+strconv.Itoa(number+1)+"."), err)
+			}
+
+			parent, key, err := resolveTomlKey(root, header)
+			if err != nil {
+				return nil, err
+			}
+
+			items := mappingValue(parent, key)
+			if items == nil {
+				items = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+				setMappingValue(parent, key, items)
+			}
+
+			table = newMappingNode()
+			items.Content = append(items.Content, table)
+		case strings.HasPrefix(line, "["):
+			header, err := cutTomlHeader(line[1:], "]")
+			if err != nil {
+				return nil, errors.Join(errors.New("Invalid TOML table header in line "+strconv.Itoa(number+1)+"."), err)
+			}
+
+			parent, key, err := resolveTomlKey(root, header)
+			if err != nil {
+				return nil, err
+			}
+
+			table = mappingValue(parent, key)
+			if table == nil {
+				table = newMappingNode()
+				setMappingValue(parent, key, table)
+			}
+		default:
+			key, value, found := strings.Cut(line, "=")
+			if !found {
+				return nil, errors.New("Invalid TOML in line " + strconv.Itoa(number+1) + ".")
+			}
+
+			parent, lastKey, err := resolveTomlKey(table, strings.TrimSpace(key))
+			if err != nil {
+				return nil, err
+			}
+
+			node, err := parseTomlValue(strings.TrimSpace(value))
+			if err != nil {
+				return nil, errors.Join(errors.New("Invalid TOML value in line "+strconv.Itoa(number+1)+"."), err)
+			}
+
+			setMappingValue(parent, lastKey, node)
+		}
+	}
+
+	return root, nil
+}
+
+// Returns the key of the given table header without the opening bracket, up to the given closing bracket.
+// The closing bracket may only be followed by a comment. Brackets within quoted keys are ignored.
+func cutTomlHeader(header string, closing string) (string, error) {
+	quote := rune(0)
+	for index, character := range header {
+		switch {
+		case quote != 0 && character == quote:
+			quote = 0
+		case quote != 0:
+			continue
+		case character == '"' || character == '\'':
+			quote = character
+		case strings.HasPrefix(header[index:], closing):
+			if rest := strings.TrimSpace(header[index+len(closing):]); rest != "" && !strings.HasPrefix(rest, "#") {
+				return "", errors.New("Unexpected content after the table header.")
+			}
+
+			return strings.TrimSpace(header[:index]), nil
+		}
+	}
+
+	return "", errors.New("Missing '" + closing + "' for the table header.")
+}
+
+// Resolves the given dotted key within the given table, creating missing tables. Returns the parent mapping and the last key.
+// If a key refers to an array of tables, its last table is used.
+func resolveTomlKey(table *yaml.Node, dottedKey string) (*yaml.Node, string, error) {
+	keys := splitTomlKey(dottedKey)
+	if len(keys) == 0 {
+		return nil, "", errors.New("The TOML key '" + dottedKey + "' is invalid.")
+	}
+
+	for _, key := range keys[:len(keys)-1] {
+		next := mappingValue(table, key)
+		switch {
+		case next == nil:
+			next = newMappingNode()
+			setMappingValue(table, key, next)
+		case next.Kind == yaml.SequenceNode && len(next.Content) > 0:
+			next = next.Content[len(next.Content)-1]
+		}
+
+		if next.Kind != yaml.MappingNode {
+			return nil, "", errors.New("The TOML key '" + dottedKey + "' conflicts with an existing value.")
+		}
+
+		table = next
+	}
+
+	return table, keys[len(keys)-1], nil
+}
+
+// Splits the given dotted TOML key into its parts. Quoted parts may contain dots.
+func splitTomlKey(dottedKey string) []string {
+	var keys []string
+	var current strings.Builder
+	quote := rune(0)
+
+	for _, character := range dottedKey {
+		switch {
+		case quote != 0 && character == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(character)
+		case character == '"' || character == '\'':
+			quote = character
+		case character == '.':
+			keys = append(keys, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(character)
+		}
+	}
+
+	keys = append(keys, strings.TrimSpace(current.String()))
+	if quote != 0 || slices.Contains(keys, "") {
+		return nil
+	}
+
+	return keys
+}
+
+// Parses the given single-line TOML value. Strings, numbers, booleans, dates and arrays share their syntax with YAML
+// flow scalars and sequences closely enough to be parsed as YAML.
+func parseTomlValue(value string) (*yaml.Node, error) {
+	if strings.HasPrefix(value, "{") || strings.HasPrefix(value, `"""`) || strings.HasPrefix(value, "'''") {
+		return nil, errors.New("Inline tables and multi-line strings are not supported.")
+	}
+
+	// Comments after values are removed, unless the value is a string which might contain '#'.
+	if !strings.HasPrefix(value, `"`) && !strings.HasPrefix(value, "'") {
+		if index := strings.Index(value, "#"); index >= 0 {
+			value = strings.TrimSpace(value[:index])
+		}
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &document); err != nil {
+		return nil, err
+	}
+
+	root := documentRoot(&document)
+	if root == nil {
+		return nil, errors.New("The value is empty.")
+	}
+
+	return root, nil
+}
+
+// Parses the given properties file into a YAML mapping. Dotted keys are nested, e.g. 'logging.level' becomes
+// the key 'level' within the mapping 'logging', unless a key is also the prefix of another key. All values are strings.
+func parsePropertiesFile(content string) *yaml.Node {
+	keys, values := parsePropertiesEntries(content)
+
+	nested := !slices.ContainsFunc(keys, func(key string) bool {
+		return slices.ContainsFunc(keys, func(other string) bool { return strings.HasPrefix(other, key+".") }) ||
+			slices.Contains(strings.Split(key, "."), "")
+	})
+
+	root := newMappingNode()
+	for i, key := range keys {
+		table, parts := root, []string{key}
+		if nested {
+			parts = strings.Split(key, ".")
+			for _, part := range parts[:len(parts)-1] {
+				next := mappingValue(table, part)
+				if next == nil {
+					next = newMappingNode()
+					setMappingValue(table, part, next)
+				}
+
+				table = next
+			}
+		}
+
+		setMappingValue(table, parts[len(parts)-1], &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: values[i]})
+	}
+
+	return root
+}
+
+// Parses the entries of the given properties file into their keys and values, in the order of the file.
+func parsePropertiesEntries(content string) ([]string, []string) {
+	var keys, values []string
+
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		// Lines ending with a backslash are continued on the next line.
+		for strings.HasSuffix(line, `\`) && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, `\`) + strings.TrimSpace(lines[i])
+		}
+
+		separator := strings.IndexAny(line, "=: \t")
+		key, value := line, ""
+		if separator >= 0 {
+			key, value = line[:separator], strings.TrimSpace(line[separator+1:])
+			value = strings.TrimSpace(strings.TrimLeft(value, "=:"))
+		}
+
+		keys, values = append(keys, key), append(values, value)
+	}
+
+	return keys, values
+}
+
+// Creates an empty YAML mapping node.
+func newMappingNode() *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+// Sets the value of the given key within the given mapping, replacing an existing value.
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+
+			return
+		}
+	}
+
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
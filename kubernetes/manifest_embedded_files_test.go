@@ -0,0 +1,151 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDetectEmbeddedFileFormatUsesExtensionAndContent(t *testing.T) {
+	tests := []struct {
+		key      string
+		content  string
+		expected embeddedFileFormat
+	}{
+		{"config.yaml", "a: 1", embeddedFileFormatYaml},
+		{"config.YML", "a: 1", embeddedFileFormatYaml},
+		{"config.json", "{}", embeddedFileFormatJson},
+		{"config.toml", "a = 1", embeddedFileFormatToml},
+		{"app.properties", "a=1", embeddedFileFormatProperties},
+		{"config", `{"a": 1}`, embeddedFileFormatJson},
+		{"config", "a: 1\nb: 2\n", embeddedFileFormatYaml},
+		{"greeting", "hello: world", embeddedFileFormatNone},
+		{"script", "#!/bin/sh\necho hello\n", embeddedFileFormatNone},
+	}
+
+	for _, test := range tests {
+		if result := detectEmbeddedFileFormat(test.key, test.content); result != test.expected {
+			t.Fatal("The format of '"+test.key+"' should be '"+string(test.expected)+"', got '"+string(result)+"'.", test.content)
+		}
+	}
+}
+
+func TestParseEmbeddedFileParsesToml(t *testing.T) {
+	content := `# Comment
+title = "Example # 1"
+owner.name = 'Tom'
+
+[database]
+ports = [8000, 8001]
+enabled = true # Comment
+
+[[servers]]
+name = "alpha"
+
+[[servers]]
+name = "beta"
+
+[servers.limits]
+cpu = 2
+`
+	expected := `{"database":{"enabled":true,"ports":[8000,8001]},"owner":{"name":"Tom"},"servers":[{"name":"alpha"},{"limits":{"cpu":2},"name":"beta"}],"title":"Example # 1"}`
+
+	node, err := parseEmbeddedFile(embeddedFileFormatToml, content)
+	if err != nil {
+		t.Fatal("Parsing the TOML file should not fail.", err)
+	}
+
+	result, _ := json.Marshal(decodeNode(node))
+	if string(result) != expected {
+		t.Fatal("The TOML file should be parsed into nested mappings. Result: " + string(result))
+	}
+}
+
+func TestParseEmbeddedFileRejectsUnsupportedToml(t *testing.T) {
+	for _, content := range []string{"invalid", "a = { b = 1 }", "a = \"\"\"\ntext\n\"\"\"", "a = 1\na.b = 2", "[\"unclosed]", "[table] b = 1", "[[table]"} {
+		if _, err := parseEmbeddedFile(embeddedFileFormatToml, content); err == nil {
+			t.Fatal("Parsing unsupported TOML should fail.", content)
+		}
+	}
+}
+
+func TestParseEmbeddedFileParsesProperties(t *testing.T) {
+	content := `# Comment
+! Comment
+logging.level=info
+logging.file : app.log
+server.port 8080
+description = first \
+  second
+`
+	expected := `{"description":"first second","logging":{"file":"app.log","level":"info"},"server":{"port":"8080"}}`
+
+	node, err := parseEmbeddedFile(embeddedFileFormatProperties, content)
+	if err != nil {
+		t.Fatal("Parsing the properties file should not fail.", err)
+	}
+
+	result, _ := json.Marshal(decodeNode(node))
+	if string(result) != expected {
+		t.Fatal("The properties file should be parsed into nested mappings. Result: " + string(result))
+	}
+}
+
+func TestParseEmbeddedFileKeepsConflictingPropertiesFlat(t *testing.T) {
+	node, err := parseEmbeddedFile(embeddedFileFormatProperties, "logging=enabled\nlogging.level=info\n")
+	if err != nil {
+		t.Fatal("Parsing the properties file should not fail.", err)
+	}
+
+	result, _ := json.Marshal(decodeNode(node))
+	if string(result) != `{"logging":"enabled","logging.level":"info"}` {
+		t.Fatal("Properties should not be nested if a key is the prefix of another key. Result: " + string(result))
+	}
+}
+
+func TestParseEmbeddedFileAllowsCommentsAfterTomlTableHeaders(t *testing.T) {
+	node, err := parseEmbeddedFile(embeddedFileFormatToml, "[\"a]#b\"] # Comment\nc = 1\n[[d]] # Comment\ne = 2\n")
+	if err != nil {
+		t.Fatal("Parsing the TOML file should not fail.", err)
+	}
+
+	result, _ := json.Marshal(decodeNode(node))
+	if string(result) != `{"a]#b":{"c":1},"d":[{"e":2}]}` {
+		t.Fatal("Comments after table headers should be ignored. Result: " + string(result))
+	}
+}
+
+func TestParseEmbeddedFileRejectsMultipleYamlDocuments(t *testing.T) {
+	if _, err := parseEmbeddedFile(embeddedFileFormatYaml, "a: 1\n---\nb: 2\n"); err == nil {
+		t.Fatal("Parsing YAML files with multiple documents should fail.")
+	}
+}
+
+func TestCanonicalizeEmbeddedFileIgnoresFormatting(t *testing.T) {
+	tests := []struct {
+		key      string
+		contents []string
+		expected string
+	}{
+		{"config.json", []string{`{"b": [1, 2], "a": {"c": 1.50}}`, "{\n\t\"a\": {\"c\": 1.50},\n\t\"b\": [1,2]\n}"}, "{\n  \"a\": {\n    \"c\": 1.50\n  },\n  \"b\": [\n    1,\n    2\n  ]\n}\n"},
+		{"config.yaml", []string{"b: [1, 2]\na: {c: 'x'} # Comment\n", "a:\n  c: x\nb:\n- 1\n- 2\n"}, "a:\n  c: x\nb:\n  - 1\n  - 2\n"},
+		{"config.toml", []string{"b = 1\n[t]\nx = 'y'\n[[s]]\nn = 1\n", "b = 1 # Comment\n\n[[s]] # Comment\nn = 1\n\n[t]\nx = \"y\"\n"}, "b = 1\n\n[[s]]\nn = 1\n\n[t]\nx = \"y\"\n"},
+		{"app.properties", []string{"b=2\na : 1\n", "# Comment\na=1\nb 2\n"}, "a=1\nb=2\n"},
+	}
+
+	for _, test := range tests {
+		for _, content := range test.contents {
+			result, ok := canonicalizeEmbeddedFile(test.key, content)
+			if !ok || result != test.expected {
+				t.Fatal("The embedded file '"+test.key+"' should be canonicalized. Result:\n"+result, content)
+			}
+		}
+	}
+}
+
+func TestCanonicalizeEmbeddedFileSkipsScalarsAndInvalidFiles(t *testing.T) {
+	for key, content := range map[string]string{"config.json": "{invalid", "config.yaml": "text", "script": "echo hello"} {
+		if _, ok := canonicalizeEmbeddedFile(key, content); ok {
+			t.Fatal("Only embedded files with collections should be canonicalized.", key)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package kubernetes
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A change of a single field between two versions of a manifest.
+// The path uses the syntax of ignore rules, e.g. 'metadata.annotations["checksum/config"]', with list items
+// being identified by their merge key if possible, e.g. 'spec.template.spec.containers[name=app].image'.
+// The old value is nil for added fields and the new value is nil for removed fields.
+// For reordered lists, the values contain the old and new order of the merge key values.
+// For modified resource quantities and durations, the normalized values are set as well, e.g. '0.5' for '500m'.
+type FieldChange struct {
+	Path               string
+	ChangeType         ChangeType
+	OldValue           any
+	NewValue           any
+	OldNormalizedValue string
+	NewNormalizedValue string
+}
+
+// Keys which can be used in paths without quoting.
+var plainPathKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// The state of the comparison of two YAML documents for field changes.
+type fieldComparison struct {
+	manifest  *Manifest
+	mergeKeys []MergeKey
+	changes   []FieldChange
+}
+
+// Creates the list of changed fields between two manifests by walking their parsed YAML documents.
+// List items are matched using the given merge keys, see compareSequences. Added and removed manifests do not have field changes.
+func CreateFieldChanges(old *Manifest, new *Manifest, mergeKeys []MergeKey) ([]FieldChange, error) {
+	if old.Content == "" || new.Content == "" {
+		return nil, nil
+	}
+
+	var oldDocument, newDocument yaml.Node
+	if err := yaml.Unmarshal([]byte(old.Content), &oldDocument); err != nil {
+		return nil, errors.Join(errors.New("Parsing manifest '"+old.ID().String()+"' for field changes failed."), err)
+	}
+
+	if err := yaml.Unmarshal([]byte(new.Content), &newDocument); err != nil {
+		return nil, errors.Join(errors.New("Parsing manifest '"+new.ID().String()+"' for field changes failed."), err)
+	}
+
+	comparison := fieldComparison{manifest: new, mergeKeys: mergeKeys}
+	comparison.compareNodes("", nil, documentRoot(&oldDocument), documentRoot(&newDocument))
+
+	return comparison.changes, nil
+}
+
+// Returns the root node of the given document, or nil for an empty document.
+func documentRoot(document *yaml.Node) *yaml.Node {
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	return document.Content[0]
+}
+
+// Compares the given nodes at the given path and location and collects all changes.
+// The location contains the keys of all parent mappings, with '[]' for list items.
+func (c *fieldComparison) compareNodes(path string, location []string, old *yaml.Node, new *yaml.Node) {
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		c.changes = append(c.changes, FieldChange{Path: path, ChangeType: ChangeTypeAdded, NewValue: decodeNode(new)})
+	case new == nil:
+		c.changes = append(c.changes, FieldChange{Path: path, ChangeType: ChangeTypeRemoved, OldValue: decodeNode(old)})
+	case old.Kind == yaml.MappingNode && new.Kind == yaml.MappingNode:
+		c.compareMappings(path, location, old, new)
+	case old.Kind == yaml.SequenceNode && new.Kind == yaml.SequenceNode:
+		c.compareSequences(path, location, old, new)
+	case old.Kind == yaml.ScalarNode && new.Kind == yaml.ScalarNode && old.Value == new.Value && old.ShortTag() == new.ShortTag():
+		return
+	case c.compareEmbeddedFiles(path, location, old, new):
+		return
+	default:
+		change := FieldChange{Path: path, ChangeType: ChangeTypeModified, OldValue: decodeNode(old), NewValue: decodeNode(new)}
+		if old.Kind == yaml.ScalarNode && new.Kind == yaml.ScalarNode {
+			oldValue, oldOk := normalizeFieldValue(c.manifest, location, old.Value)
+			newValue, newOk := normalizeFieldValue(c.manifest, location, new.Value)
+			if oldOk && newOk {
+				change.OldNormalizedValue, change.NewNormalizedValue = oldValue, newValue
+			}
+		}
+
+		c.changes = append(c.changes, change)
+	}
+}
+
+// Compares the files embedded in the data of a ConfigMap structurally, e.g. 'data["config.yaml"].logging.level'.
+// Returns false if the values are not embedded files of the same format, so that they are compared as scalars.
+func (c *fieldComparison) compareEmbeddedFiles(path string, location []string, old *yaml.Node, new *yaml.Node) bool {
+	if !isEmbeddedFileLocation(c.manifest, location) {
+		return false
+	}
+
+	if old.Kind != yaml.ScalarNode || new.Kind != yaml.ScalarNode {
+		return false
+	}
+
+	format := detectEmbeddedFileFormat(location[1], old.Value)
+	if format == embeddedFileFormatNone || format != detectEmbeddedFileFormat(location[1], new.Value) {
+		return false
+	}
+
+	oldFile, err := parseEmbeddedFile(format, old.Value)
+	if err != nil {
+		return false
+	}
+
+	newFile, err := parseEmbeddedFile(format, new.Value)
+	if err != nil {
+		return false
+	}
+
+	// Files which only contain a scalar are compared as text, as their location would otherwise be compared again.
+	if !isCollectionNode(oldFile) || !isCollectionNode(newFile) {
+		return false
+	}
+
+	c.compareNodes(path, location, oldFile, newFile)
+
+	return true
+}
+
+// Determines whether the given node is a mapping or a sequence.
+func isCollectionNode(node *yaml.Node) bool {
+	return node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode
+}
+
+// Compares the fields of two mappings. Fields are compared in the order of the old mapping, followed by new fields.
+func (c *fieldComparison) compareMappings(path string, location []string, old *yaml.Node, new *yaml.Node) {
+	keys := mappingKeys(old)
+	for _, key := range mappingKeys(new) {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		c.compareNodes(appendKeyToPath(path, key), append(slices.Clip(location), key), mappingValue(old, key), mappingValue(new, key))
+	}
+}
+
+// Compares the items of two sequences, see matchSequenceItems.
+func (c *fieldComparison) compareSequences(path string, location []string, old *yaml.Node, new *yaml.Node) {
+	itemLocation := append(slices.Clip(location), "[]")
+	for _, pair := range matchSequenceItems(c.manifest, c.mergeKeys, location, old, new) {
+		c.compareNodes(path+pair.Selector, itemLocation, pair.Old, pair.New)
+	}
+}
+
+// A pair of matching items of two sequences. The selector identifies the items in paths, e.g. '[name=app]' or '[0]'.
+// One of the items is nil if it only exists in one of the sequences.
+type sequenceItemPair struct {
+	Selector string
+	Old      *yaml.Node
+	New      *yaml.Node
+}
+
+// Matches the items of two sequences by their merge key, falling back to their name, if all items have a unique value for it.
+// Otherwise, items are matched by their index.
+func matchSequenceItems(manifest *Manifest, mergeKeys []MergeKey, location []string, old *yaml.Node, new *yaml.Node) []sequenceItemPair {
+	var pairs []sequenceItemPair
+
+	mergeKey := cmp.Or(findMergeKey(mergeKeys, manifest, location), "name")
+	if !hasUniqueMergeKey(old, mergeKey) || !hasUniqueMergeKey(new, mergeKey) {
+		for i := 0; i < max(len(old.Content), len(new.Content)); i++ {
+			pairs = append(pairs, sequenceItemPair{"[" + strconv.Itoa(i) + "]", sequenceItem(old, i), sequenceItem(new, i)})
+		}
+
+		return pairs
+	}
+
+	names := sequenceMergeKeyValues(old, mergeKey)
+	for _, name := range sequenceMergeKeyValues(new, mergeKey) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		pairs = append(pairs, sequenceItemPair{"[" + mergeKey + "=" + name + "]", findSequenceItem(old, mergeKey, name), findSequenceItem(new, mergeKey, name)})
+	}
+
+	return pairs
+}
+
+// Returns the keys of the given mapping in their order.
+func mappingKeys(node *yaml.Node) []string {
+	var keys []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+
+	return keys
+}
+
+// Returns the value of the given key within the given mapping, or nil if the key does not exist.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// Returns the item with the given index of the given sequence, or nil if the index is out of range.
+func sequenceItem(node *yaml.Node, index int) *yaml.Node {
+	if index >= len(node.Content) {
+		return nil
+	}
+
+	return node.Content[index]
+}
+
+// Determines whether all items of the given sequence are mappings with a unique scalar value for the given key.
+func hasUniqueMergeKey(node *yaml.Node, mergeKey string) bool {
+	values := sequenceMergeKeyValues(node, mergeKey)
+
+	return len(values) == len(node.Content) && len(slices.Compact(slices.Sorted(slices.Values(values)))) == len(values)
+}
+
+// Returns the scalar values of the given key of all mapping items of the given sequence.
+func sequenceMergeKeyValues(node *yaml.Node, mergeKey string) []string {
+	var values []string
+	for _, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if value := mappingValue(item, mergeKey); value != nil && value.Kind == yaml.ScalarNode {
+			values = append(values, value.Value)
+		}
+	}
+
+	return values
+}
+
+// Returns the mapping item of the given sequence whose key has the given value, or nil if there is none.
+func findSequenceItem(node *yaml.Node, mergeKey string, value string) *yaml.Node {
+	for _, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if itemValue := mappingValue(item, mergeKey); itemValue != nil && itemValue.Kind == yaml.ScalarNode && itemValue.Value == value {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// Appends the given key to the path. Keys with special characters are quoted, e.g. 'metadata.labels["app.kubernetes.io/name"]'.
+func appendKeyToPath(path string, key string) string {
+	if !plainPathKeyPattern.MatchString(key) {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// Decodes the given node into a plain value, e.g. a string, number, map or slice.
+func decodeNode(node *yaml.Node) any {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+
+	return value
+}
+
+// Returns a readable representation of the change, e.g. 'spec.replicas: 1 → 2'. Missing values are shown as '(none)'.
+// Normalized values are appended if they differ from the values, e.g. 'resources.limits.cpu: 500m → 1 (normalized: 0.5 → 1)'.
+func (c FieldChange) String() string {
+	oldValue, newValue := formatFieldValue(c.OldValue), formatFieldValue(c.NewValue)
+	switch c.ChangeType {
+	case ChangeTypeAdded:
+		oldValue = "(none)"
+	case ChangeTypeRemoved:
+		newValue = "(none)"
+	case ChangeTypeReordered:
+		return c.Path + ": reordered " + oldValue + " → " + newValue
+	}
+
+	result := c.Path + ": " + oldValue + " → " + newValue
+	if c.OldNormalizedValue != "" && (c.OldNormalizedValue != oldValue || c.NewNormalizedValue != newValue) {
+		result += " (normalized: " + c.OldNormalizedValue + " → " + c.NewNormalizedValue + ")"
+	}
+
+	return result
+}
+
+// Formats the given field value on a single line. Strings are printed as they are unless they are empty
+// or contain line breaks, all other values are printed as JSON.
+func formatFieldValue(value any) string {
+	if text, ok := value.(string); ok {
+		if text == "" || strings.Contains(text, "\n") {
+			return strconv.Quote(text)
+		}
+
+		return text
+	}
+
+	result, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(result)
+}
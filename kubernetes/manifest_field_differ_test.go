@@ -0,0 +1,206 @@
+package kubernetes
+
+import "testing"
+
+func TestCreateFieldChangesListsChangedFields(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "app", Content: `metadata:
+  name: app
+  labels:
+    team: payments
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+        - name: sidecar
+          image: proxy:1
+        - name: app
+          image: my-app:1.0
+`}
+	newManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "app", Content: `metadata:
+  name: app
+  labels:
+    app.kubernetes.io/name: app
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: app
+          image: my-app:1.1
+        - name: sidecar
+          image: proxy:1
+`}
+
+	changes, err := CreateFieldChanges(&oldManifest, &newManifest, nil)
+	if err != nil {
+		t.Fatal("Creating field changes should not fail.", err)
+	}
+
+	expectedChanges := []string{
+		"metadata.labels.team: payments → (none)",
+		`metadata.labels["app.kubernetes.io/name"]: (none) → app`,
+		"spec.replicas: 1 → 2",
+		"spec.template.spec.containers[name=app].image: my-app:1.0 → my-app:1.1",
+	}
+
+	if len(changes) != len(expectedChanges) {
+		t.Fatal("All changed fields should be listed.", changes)
+	}
+
+	for i, expected := range expectedChanges {
+		if changes[i].String() != expected {
+			t.Fatal("The field change should be '" + expected + "', got '" + changes[i].String() + "'.")
+		}
+	}
+}
+
+func TestCreateFieldChangesMatchesListItemsWithoutNameByIndex(t *testing.T) {
+	oldManifest := Manifest{Content: "args:\n  - --verbose\n  - --port=80\n"}
+	newManifest := Manifest{Content: "args:\n  - --verbose\n  - --port=81\n  - --debug\n"}
+
+	changes, err := CreateFieldChanges(&oldManifest, &newManifest, nil)
+	if err != nil {
+		t.Fatal("Creating field changes should not fail.", err)
+	}
+
+	if len(changes) != 2 || changes[0].String() != "args[1]: --port=80 → --port=81" || changes[1].ChangeType != ChangeTypeAdded || changes[1].Path != "args[2]" {
+		t.Fatal("List items without name should be compared by index.", changes)
+	}
+}
+
+func TestCreateFieldChangesFormatsComplexValuesAsJson(t *testing.T) {
+	oldManifest := Manifest{Content: "spec:\n  ports: []\n"}
+	newManifest := Manifest{Content: "spec:\n  ports:\n  - port: 80\n  selector:\n    app: web\n"}
+
+	changes, err := CreateFieldChanges(&oldManifest, &newManifest, nil)
+	if err != nil {
+		t.Fatal("Creating field changes should not fail.", err)
+	}
+
+	if len(changes) != 2 || changes[0].String() != `spec.ports[0]: (none) → {"port":80}` || changes[1].String() != `spec.selector: (none) → {"app":"web"}` {
+		t.Fatal("Complex values should be formatted as JSON.", changes)
+	}
+}
+
+func TestCreateFieldChangesSkipsAddedAndRemovedManifests(t *testing.T) {
+	manifest := Manifest{Content: "spec:\n  replicas: 1\n"}
+
+	changes, err := CreateFieldChanges(&Manifest{}, &manifest, nil)
+	if err != nil || changes != nil {
+		t.Fatal("Added manifests should not have field changes.", changes, err)
+	}
+}
+
+func TestCreateFieldChangesMatchesListItemsByMergeKey(t *testing.T) {
+	oldManifest := Manifest{Kind: "Pod", Content: "spec:\n  containers:\n    - name: app\n      ports:\n        - containerPort: 80\n          protocol: TCP\n"}
+	newManifest := Manifest{Kind: "Pod", Content: "spec:\n  containers:\n    - name: app\n      ports:\n        - containerPort: 8080\n        - containerPort: 80\n          protocol: UDP\n"}
+
+	changes, err := CreateFieldChanges(&oldManifest, &newManifest, DefaultMergeKeys)
+	if err != nil {
+		t.Fatal("Creating field changes should not fail.", err)
+	}
+
+	expectedChanges := []string{
+		"spec.containers[name=app].ports[containerPort=80].protocol: TCP → UDP",
+		`spec.containers[name=app].ports[containerPort=8080]: (none) → {"containerPort":8080}`,
+	}
+
+	if len(changes) != len(expectedChanges) || changes[0].String() != expectedChanges[0] || changes[1].String() != expectedChanges[1] {
+		t.Fatal("List items should be matched by their merge key.", changes)
+	}
+}
+
+func TestCreateFieldChangesComparesFilesEmbeddedInConfigMaps(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "app", Content: `data:
+  config.yaml: |
+    logging:
+      level: info
+  settings.json: '{"timeout": 10}'
+  app.toml: |
+    [database]
+    port = 5432
+  app.properties: |
+    server.port=8080
+  plain: hello
+`}
+	newManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "app", Content: `data:
+  config.yaml: |
+    logging:
+      level: debug
+  settings.json: '{"timeout": 20}'
+  app.toml: |
+    [database]
+    port = 5433
+  app.properties: |
+    server.port=8081
+  plain: world
+`}
+
+	changes, err := CreateFieldChanges(&oldManifest, &newManifest, nil)
+	if err != nil {
+		t.Fatal("Creating field changes should not fail.", err)
+	}
+
+	expectedChanges := []string{
+		`data["config.yaml"].logging.level: info → debug`,
+		`data["settings.json"].timeout: 10 → 20`,
+		`data["app.toml"].database.port: 5432 → 5433`,
+		`data["app.properties"].server.port: 8080 → 8081`,
+		"data.plain: hello → world",
+	}
+
+	if len(changes) != len(expectedChanges) {
+		t.Fatal("All changed fields of embedded files should be listed.", changes)
+	}
+
+	for i, expected := range expectedChanges {
+		if changes[i].String() != expected {
+			t.Fatal("The field change should be '" + expected + "', got '" + changes[i].String() + "'.")
+		}
+	}
+}
+
+func TestCreateFieldChangesComparesInvalidEmbeddedFilesAsText(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Content: "data:\n  config.json: '{\"a\": 1}'\n"}
+	newManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Content: "data:\n  config.json: '{\"a\": '\n"}
+
+	changes, err := CreateFieldChanges(&oldManifest, &newManifest, nil)
+	if err != nil {
+		t.Fatal("Creating field changes should not fail.", err)
+	}
+
+	if len(changes) != 1 || changes[0].String() != `data["config.json"]: {"a": 1} → {"a": ` {
+		t.Fatal("Embedded files which cannot be parsed should be compared as text.", changes)
+	}
+}
+
+func TestCreateFieldChangesComparesScalarEmbeddedFilesAsText(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Content: "data:\n  level.yaml: info\n  limit.json: '10'\n"}
+	newManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Content: "data:\n  level.yaml: debug\n  limit.json: '20'\n"}
+
+	changes, err := CreateFieldChanges(&oldManifest, &newManifest, nil)
+	if err != nil {
+		t.Fatal("Creating field changes should not fail.", err)
+	}
+
+	if len(changes) != 2 || changes[0].String() != `data["level.yaml"]: info → debug` || changes[1].String() != `data["limit.json"]: 10 → 20` {
+		t.Fatal("Embedded files which only contain a scalar should be compared as text.", changes)
+	}
+}
+
+func TestCreateFieldChangesListsNormalizedQuantities(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "v1", Kind: "Pod", Content: "spec:\n  containers:\n  - name: app\n    resources:\n      limits:\n        cpu: 500m\n        memory: 1Gi\n"}
+	newManifest := Manifest{ApiVersion: "v1", Kind: "Pod", Content: "spec:\n  containers:\n  - name: app\n    resources:\n      limits:\n        cpu: 1\n        memory: 2Gi\n"}
+
+	changes, err := CreateFieldChanges(&oldManifest, &newManifest, nil)
+	if err != nil {
+		t.Fatal("Creating field changes should not fail.", err)
+	}
+
+	if len(changes) != 2 ||
+		changes[0].String() != "spec.containers[name=app].resources.limits.cpu: 500m → 1 (normalized: 0.5 → 1)" ||
+		changes[1].String() != "spec.containers[name=app].resources.limits.memory: 1Gi → 2Gi (normalized: 1073741824 → 2147483648)" {
+		t.Fatal("Changed quantities should list their normalized values.", changes)
+	}
+}
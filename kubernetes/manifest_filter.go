@@ -0,0 +1,209 @@
+package kubernetes
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The field of a resource a filter is applied to.
+type FilterField string
+
+const (
+	FilterFieldKind      FilterField = "kind"
+	FilterFieldNamespace FilterField = "namespace"
+	FilterFieldName      FilterField = "name"
+	FilterFieldLabel     FilterField = "label"
+)
+
+// A filter which matches resources by kind, namespace or name (using glob patterns, see path.Match) or by label selector.
+type ResourceFilter struct {
+	Field    FilterField
+	Pattern  string
+	Selector []labelRequirement
+}
+
+// A single requirement of a label selector, e.g. 'team=payments', 'tier!=db', 'team' or '!team'.
+type labelRequirement struct {
+	Key      string
+	Value    string
+	HasValue bool
+	Negated  bool
+}
+
+// Filters which decide which resources are part of a diff.
+// Filters on the same field are combined with OR, filters on different fields with AND. A resource is part of the diff
+// if it matches the include filters (or there are none) and does not match any exclude filter.
+type ResourceFilters struct {
+	Include []ResourceFilter
+	Exclude []ResourceFilter
+}
+
+// Parses a filter of the form 'field=value', e.g. 'kind=CustomResourceDefinition', 'namespace=team-a-*', 'name=api-*'
+// or 'label=team=payments,tier!=db'. The requirements of a label selector are separated by commas and must all match.
+func ParseResourceFilter(filter string) (ResourceFilter, error) {
+	field, value, found := strings.Cut(filter, "=")
+	if !found || value == "" {
+		return ResourceFilter{}, errors.New("The filter '" + filter + "' is invalid: must be of the form 'field=value'.")
+	}
+
+	switch FilterField(field) {
+	case FilterFieldKind, FilterFieldNamespace, FilterFieldName:
+		if _, err := path.Match(value, ""); err != nil {
+			return ResourceFilter{}, errors.Join(errors.New("The filter '"+filter+"' is invalid: '"+value+"' is not a valid pattern."), err)
+		}
+
+		return ResourceFilter{Field: FilterField(field), Pattern: value}, nil
+	case FilterFieldLabel:
+		selector, err := parseLabelSelector(value)
+		if err != nil {
+			return ResourceFilter{}, errors.Join(errors.New("The filter '"+filter+"' is invalid."), err)
+		}
+
+		return ResourceFilter{Field: FilterFieldLabel, Pattern: value, Selector: selector}, nil
+	default:
+		return ResourceFilter{}, errors.New("The filter field '" + field + "' is invalid: must be one of 'kind', 'namespace', 'name' or 'label'.")
+	}
+}
+
+// Parses the given label selector, consisting of comma-separated requirements.
+func parseLabelSelector(selector string) ([]labelRequirement, error) {
+	var requirements []labelRequirement
+
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+
+		var requirement labelRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			requirement.Key, requirement.Value, _ = strings.Cut(part, "!=")
+			requirement.HasValue, requirement.Negated = true, true
+		case strings.Contains(part, "="):
+			// Both 'key=value' and 'key==value' are supported.
+			requirement.Key, requirement.Value, _ = strings.Cut(part, "=")
+			requirement.Value = strings.TrimPrefix(requirement.Value, "=")
+			requirement.HasValue = true
+		case strings.HasPrefix(part, "!"):
+			requirement.Key, requirement.Negated = part[1:], true
+		default:
+			requirement.Key = part
+		}
+
+		requirement.Key, requirement.Value = strings.TrimSpace(requirement.Key), strings.TrimSpace(requirement.Value)
+		if requirement.Key == "" {
+			return nil, errors.New("The label selector requirement '" + part + "' has no label key.")
+		}
+
+		requirements = append(requirements, requirement)
+	}
+
+	return requirements, nil
+}
+
+// Determines whether the filter matches the given manifest with the given labels.
+func (f ResourceFilter) matches(manifest *Manifest, labels map[string]string) bool {
+	var value string
+	switch f.Field {
+	case FilterFieldKind:
+		value = manifest.Kind
+	case FilterFieldNamespace:
+		value = manifest.Namespace
+	case FilterFieldName:
+		value = manifest.Name
+	case FilterFieldLabel:
+		for _, requirement := range f.Selector {
+			if !requirement.matches(labels) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	matched, _ := path.Match(f.Pattern, value)
+
+	return matched
+}
+
+// Determines whether the requirement matches the given labels.
+func (r labelRequirement) matches(labels map[string]string) bool {
+	value, found := labels[r.Key]
+	if r.HasValue {
+		found = found && value == r.Value
+	}
+
+	return found != r.Negated
+}
+
+// Returns true if there are no include and exclude filters.
+func (f *ResourceFilters) IsEmpty() bool {
+	return f == nil || (len(f.Include) == 0 && len(f.Exclude) == 0)
+}
+
+// Determines whether the given manifest is part of the diff according to the filters.
+func (f *ResourceFilters) Matches(manifest *Manifest) bool {
+	if f.IsEmpty() {
+		return true
+	}
+
+	labels := parseManifestLabels(manifest)
+
+	for _, filter := range f.Exclude {
+		if filter.matches(manifest, labels) {
+			return false
+		}
+	}
+
+	// Include filters are grouped by field: at least one filter of each field must match.
+	matchedFields := make(map[FilterField]bool)
+	for _, filter := range f.Include {
+		matchedFields[filter.Field] = matchedFields[filter.Field] || filter.matches(manifest, labels)
+	}
+
+	for _, matched := range matchedFields {
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Removes all manifests from both maps which are not part of the diff according to the given filters.
+// A resource is kept if either of its versions matches the filters. Returns the number of removed resources.
+func FilterManifests(old *ManifestMap, new *ManifestMap, filters *ResourceFilters) int {
+	if filters.IsEmpty() {
+		return 0
+	}
+
+	removed := 0
+	for _, id := range *GetUniqueResourceIDs(old, new) {
+		oldManifest, oldFound := (*old)[id]
+		newManifest, newFound := (*new)[id]
+
+		if (oldFound && filters.Matches(&oldManifest)) || (newFound && filters.Matches(&newManifest)) {
+			continue
+		}
+
+		delete(*old, id)
+		delete(*new, id)
+		removed++
+	}
+
+	return removed
+}
+
+// Parses the labels of the given manifest. Manifests which cannot be parsed are treated as not having labels.
+func parseManifestLabels(manifest *Manifest) map[string]string {
+	var data struct {
+		Metadata struct {
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+	}
+
+	_ = yaml.Unmarshal([]byte(manifest.Content), &data)
+
+	return data.Metadata.Labels
+}
@@ -0,0 +1,105 @@
+package kubernetes
+
+import "testing"
+
+func TestParseResourceFilterParsesFieldAndPattern(t *testing.T) {
+	filter, err := ParseResourceFilter("namespace=team-a-*")
+	if err != nil {
+		t.Fatal("Parsing the filter should not fail.", err)
+	}
+
+	if filter.Field != FilterFieldNamespace || filter.Pattern != "team-a-*" {
+		t.Fatal("The filter should contain field and pattern.", filter)
+	}
+}
+
+func TestParseResourceFilterParsesLabelSelector(t *testing.T) {
+	filter, err := ParseResourceFilter("label=team=payments, tier!=db,managed,!legacy")
+	if err != nil {
+		t.Fatal("Parsing the filter should not fail.", err)
+	}
+
+	expectedSelector := []labelRequirement{
+		{Key: "team", Value: "payments", HasValue: true},
+		{Key: "tier", Value: "db", HasValue: true, Negated: true},
+		{Key: "managed"},
+		{Key: "legacy", Negated: true},
+	}
+
+	if len(filter.Selector) != len(expectedSelector) {
+		t.Fatal("The filter should contain all label requirements.", filter)
+	}
+
+	for i := range expectedSelector {
+		if filter.Selector[i] != expectedSelector[i] {
+			t.Fatal("The filter should contain all label requirements.", filter)
+		}
+	}
+}
+
+func TestParseResourceFilterRejectsInvalidFilters(t *testing.T) {
+	for _, filter := range []string{"", "kind", "kind=", "owner=me", "name=[a", "label=!", "label=a,,b"} {
+		if _, err := ParseResourceFilter(filter); err == nil {
+			t.Fatal("The filter '" + filter + "' should not be parsed successfully.")
+		}
+	}
+}
+
+func TestResourceFiltersMatchIncludeAndExcludeFilters(t *testing.T) {
+	deployment := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "api", Namespace: "team-a", Content: "metadata:\n  labels:\n    team: payments\n"}
+	crd := Manifest{ApiVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "foos.example.com"}
+	service := Manifest{ApiVersion: "v1", Kind: "Service", Name: "api", Namespace: "team-b"}
+
+	tests := []struct {
+		include  []string
+		exclude  []string
+		expected []bool
+	}{
+		{nil, nil, []bool{true, true, true}},
+		{nil, []string{"kind=CustomResourceDefinition"}, []bool{true, false, true}},
+		{[]string{"namespace=team-*"}, nil, []bool{true, false, true}},
+		{[]string{"namespace=team-a", "namespace=team-b", "kind=Service"}, nil, []bool{false, false, true}},
+		{[]string{"name=api"}, []string{"label=team=payments"}, []bool{false, false, true}},
+		{[]string{"label=team"}, nil, []bool{true, false, false}},
+		{[]string{"label=!team"}, nil, []bool{false, true, true}},
+	}
+
+	for _, test := range tests {
+		filters := ResourceFilters{}
+		for _, value := range test.include {
+			filter, _ := ParseResourceFilter(value)
+			filters.Include = append(filters.Include, filter)
+		}
+
+		for _, value := range test.exclude {
+			filter, _ := ParseResourceFilter(value)
+			filters.Exclude = append(filters.Exclude, filter)
+		}
+
+		for i, manifest := range []Manifest{deployment, crd, service} {
+			if filters.Matches(&manifest) != test.expected[i] {
+				t.Fatalf("The filters (include %v, exclude %v) should match '%s' = %v.", test.include, test.exclude, manifest.Kind, test.expected[i])
+			}
+		}
+	}
+}
+
+func TestFilterManifestsKeepsResourcesWithAnyMatchingVersion(t *testing.T) {
+	oldDeployment := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "api", Content: "metadata:\n  labels:\n    team: payments\n"}
+	newDeployment := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "api", Content: "metadata:\n  labels:\n    team: billing\n"}
+	crd := Manifest{ApiVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "foos.example.com"}
+
+	oldManifests := ManifestMap{oldDeployment.Key(): oldDeployment, crd.Key(): crd}
+	newManifests := ManifestMap{newDeployment.Key(): newDeployment}
+
+	filter, _ := ParseResourceFilter("label=team=payments")
+	removed := FilterManifests(&oldManifests, &newManifests, &ResourceFilters{Include: []ResourceFilter{filter}})
+
+	if removed != 1 {
+		t.Fatal("Exactly one resource should be removed by the filters.", removed)
+	}
+
+	if _, found := newManifests[newDeployment.Key()]; !found || len(oldManifests) != 1 {
+		t.Fatal("Resources with any version matching the filters should be kept.", oldManifests, newManifests)
+	}
+}
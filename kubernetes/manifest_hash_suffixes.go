@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The hash suffix which kustomize appends to the names of generated ConfigMaps and Secrets, e.g. 'app-config-7h2k9m8t5c'.
+// The hash is encoded using an alphabet without vowels and similar looking characters.
+var hashSuffixPattern = regexp.MustCompile(`^(.+)-([bcdfghkmt2456789]{10})$`)
+
+// A reference to a generated object whose hash suffix changed, e.g. in the 'envFrom' of a container.
+type hashSuffixChange struct {
+	BaseName  string
+	OldSuffix string
+	NewSuffix string
+}
+
+// Splits the given name into the base name and the hash suffix. Returns false if the name has no hash suffix.
+func splitHashSuffix(name string) (string, string, bool) {
+	match := hashSuffixPattern.FindStringSubmatch(name)
+	if match == nil {
+		return "", "", false
+	}
+
+	return match[1], match[2], true
+}
+
+// Replaces the keys of ConfigMaps and Secrets with a hash suffix by their base name, so that generated objects are
+// matched across versions even though their hash changed. Keys which are already in use are not replaced.
+func keyGeneratedManifestsByBaseName(manifests *ManifestMap) {
+	for key, manifest := range *manifests {
+		if key.Group != "" || (key.Kind != "ConfigMap" && key.Kind != "Secret") {
+			continue
+		}
+
+		baseName, _, found := splitHashSuffix(key.Name)
+		if !found {
+			continue
+		}
+
+		baseKey := key
+		baseKey.Name = baseName
+		if _, exists := (*manifests)[baseKey]; exists {
+			continue
+		}
+
+		(*manifests)[baseKey] = manifest
+		delete(*manifests, key)
+	}
+}
+
+// Replaces the values in the old node which only differ from the new node in their hash suffix by the new values,
+// so that changed references to generated objects do not show up in the line diff. The name of the generated object
+// itself is kept. Returns a note per referenced object describing the changed hash suffix, e.g.
+// 'Hash suffix of app-config changed: 7h2k9m8t5c → m4t8c2b5h9 (2 references)'.
+func alignHashSuffixReferences(manifest *Manifest, mergeKeys []MergeKey, old *yaml.Node, new *yaml.Node) []string {
+	var changes []hashSuffixChange
+	alignHashSuffixesInNodes(manifest, mergeKeys, nil, old, new, &changes)
+
+	// Multiple references to the same object are collapsed into a single note.
+	var uniqueChanges []hashSuffixChange
+	counts := make(map[hashSuffixChange]int)
+	for _, change := range changes {
+		if counts[change] == 0 {
+			uniqueChanges = append(uniqueChanges, change)
+		}
+
+		counts[change]++
+	}
+
+	var notes []string
+	for _, change := range uniqueChanges {
+		references := "references"
+		if counts[change] == 1 {
+			references = "reference"
+		}
+
+		notes = append(notes, fmt.Sprintf("Hash suffix of %s changed: %s → %s (%d %s)", change.BaseName, change.OldSuffix, change.NewSuffix, counts[change], references))
+	}
+
+	return notes
+}
+
+// Aligns the hash suffixes within the given nodes at the given location and appends the changed references to the given list.
+func alignHashSuffixesInNodes(manifest *Manifest, mergeKeys []MergeKey, location []string, old *yaml.Node, new *yaml.Node, changes *[]hashSuffixChange) {
+	if old == nil || new == nil || old.Kind != new.Kind {
+		return
+	}
+
+	switch old.Kind {
+	case yaml.MappingNode:
+		for _, key := range mappingKeys(old) {
+			alignHashSuffixesInNodes(manifest, mergeKeys, append(slices.Clip(location), key), mappingValue(old, key), mappingValue(new, key), changes)
+		}
+	case yaml.SequenceNode:
+		itemLocation := append(slices.Clip(location), "[]")
+		for _, pair := range matchSequenceItems(manifest, mergeKeys, location, old, new) {
+			alignHashSuffixesInNodes(manifest, mergeKeys, itemLocation, pair.Old, pair.New, changes)
+		}
+	case yaml.ScalarNode:
+		if old.Value == new.Value || slices.Equal(location, []string{"metadata", "name"}) {
+			return
+		}
+
+		oldBaseName, oldSuffix, oldFound := splitHashSuffix(old.Value)
+		newBaseName, newSuffix, newFound := splitHashSuffix(new.Value)
+		if !oldFound || !newFound || oldBaseName != newBaseName {
+			return
+		}
+
+		old.Value, old.Tag, old.Style = new.Value, new.Tag, new.Style
+		*changes = append(*changes, hashSuffixChange{BaseName: oldBaseName, OldSuffix: oldSuffix, NewSuffix: newSuffix})
+	}
+}
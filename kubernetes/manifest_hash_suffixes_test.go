@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"slices"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSplitHashSuffixDetectsKustomizeHashSuffixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseName string
+		suffix   string
+		found    bool
+	}{
+		{"app-config-7h2k9m8t5c", "app-config", "7h2k9m8t5c", true},
+		{"app-config-7h2k9", "", "", false},
+		{"app-config-abcdefghij", "", "", false},
+		{"app-config", "", "", false},
+		{"-7h2k9m8t5c", "", "", false},
+	}
+
+	for _, test := range tests {
+		baseName, suffix, found := splitHashSuffix(test.name)
+		if baseName != test.baseName || suffix != test.suffix || found != test.found {
+			t.Fatal("The hash suffix of '"+test.name+"' should be detected correctly.", baseName, suffix, found)
+		}
+	}
+}
+
+func TestKeyGeneratedManifestsByBaseNameOnlyReplacesKeysOfGeneratedObjects(t *testing.T) {
+	configMap := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "app-config-7h2k9m8t5c", Content: "a"}
+	secret := Manifest{ApiVersion: "v1", Kind: "Secret", Name: "app-secret-m4t8c2b5h9", Content: "b"}
+	deployment := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "app-7h2k9m8t5c", Content: "c"}
+	manifests := ManifestMap{configMap.Key(): configMap, secret.Key(): secret, deployment.Key(): deployment}
+
+	keyGeneratedManifestsByBaseName(&manifests)
+
+	expectedKeys := []ResourceID{
+		{Kind: "ConfigMap", Name: "app-config"},
+		{Kind: "Secret", Name: "app-secret"},
+		deployment.Key(),
+	}
+
+	for _, key := range expectedKeys {
+		if _, exists := manifests[key]; !exists {
+			t.Fatal("Only generated ConfigMaps and Secrets should be keyed by their base name.", manifests)
+		}
+	}
+
+	if len(manifests) != 3 || manifests[expectedKeys[0]].Name != configMap.Name {
+		t.Fatal("The manifests themselves should not be changed.", manifests)
+	}
+}
+
+func TestAlignHashSuffixReferencesCollapsesReferences(t *testing.T) {
+	oldContent := "metadata:\n  name: app-7h2k9m8t5c\nspec:\n  volumes:\n  - name: config\n    configMap:\n      name: app-config-7h2k9m8t5c\n  - name: secret\n    secret:\n      secretName: app-secret-7h2k9m8t5c\n  envFrom:\n  - configMapRef:\n      name: app-config-7h2k9m8t5c\n  image: app:1\n"
+	newContent := "metadata:\n  name: app-m4t8c2b5h9\nspec:\n  volumes:\n  - name: config\n    configMap:\n      name: app-config-m4t8c2b5h9\n  - name: secret\n    secret:\n      secretName: app-secret-fg4k2b7d9h\n  envFrom:\n  - configMapRef:\n      name: app-config-m4t8c2b5h9\n  image: app:2\n"
+	expectedContent := "metadata:\n  name: app-7h2k9m8t5c\nspec:\n  volumes:\n    - name: config\n      configMap:\n        name: app-config-m4t8c2b5h9\n    - name: secret\n      secret:\n        secretName: app-secret-fg4k2b7d9h\n  envFrom:\n    - configMapRef:\n        name: app-config-m4t8c2b5h9\n  image: app:1\n"
+
+	var oldDocument, newDocument yaml.Node
+	_ = yaml.Unmarshal([]byte(oldContent), &oldDocument)
+	_ = yaml.Unmarshal([]byte(newContent), &newDocument)
+
+	notes := alignHashSuffixReferences(&Manifest{ApiVersion: "apps/v1", Kind: "Deployment"}, DefaultMergeKeys, documentRoot(&oldDocument), documentRoot(&newDocument))
+
+	expectedNotes := []string{
+		"Hash suffix of app-config changed: 7h2k9m8t5c → m4t8c2b5h9 (2 references)",
+		"Hash suffix of app-secret changed: 7h2k9m8t5c → fg4k2b7d9h (1 reference)",
+	}
+
+	if !slices.Equal(notes, expectedNotes) {
+		t.Fatal("A note should be returned per referenced object.", notes)
+	}
+
+	result, _ := encodeYamlDocument(&oldDocument)
+	if result != expectedContent {
+		t.Fatal("Only references which differ in their hash suffix should be replaced. Result:\n" + result)
+	}
+}
@@ -0,0 +1,323 @@
+package kubernetes
+
+import (
+	"cmp"
+	"errors"
+	"html/template"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// Options which control how the HTML report is printed.
+// A negative number of context lines prints all lines instead of hunks with the given context.
+// If field changes are enabled, the changed fields of each resource are listed before its diff.
+type HtmlReportOptions struct {
+	Title        string
+	ContextLines int
+	SideBySide   bool
+	FieldChanges bool
+}
+
+// The data passed to the HTML report template.
+type htmlReport struct {
+	Title        string
+	Summary      DiffSummary
+	Namespaces   []htmlNamespace
+	Resources    []htmlResource
+	DefaultsOnly []htmlDefaultsOnlyResource
+	SideBySide   bool
+}
+
+// A resource which only differs in default values within the HTML report.
+type htmlDefaultsOnlyResource struct {
+	Header       string
+	FieldChanges []FieldChange
+}
+
+// A namespace within the resource index of the HTML report.
+type htmlNamespace struct {
+	Name  string
+	Kinds []htmlKind
+}
+
+// A kind within a namespace of the resource index of the HTML report.
+type htmlKind struct {
+	Name      string
+	Resources []htmlResource
+}
+
+// A changed resource within the HTML report.
+type htmlResource struct {
+	Anchor       string
+	Header       string
+	ID           ResourceID
+	ChangeType   ChangeType
+	LinesAdded   int
+	LinesRemoved int
+	FieldChanges []FieldChange
+	Notes        []string
+	Hunks        []htmlHunk
+}
+
+// A hunk of a resource diff within the HTML report.
+type htmlHunk struct {
+	Header string
+	Lines  []htmlLine
+	Pairs  []DiffLinePair
+}
+
+// A line of a unified resource diff within the HTML report.
+type htmlLine struct {
+	Class     string
+	OldNumber int
+	NewNumber int
+	Prefix    string
+	Text      string
+}
+
+// The template of the HTML report. It is self-contained and does not load any external resources.
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.6em; }
+table.summary td, table.summary th { padding: 0.2em 0.8em; text-align: right; }
+table.summary th { text-align: left; }
+ul.index { list-style: none; padding-left: 1em; }
+details { border: 1px solid #d0d7de; border-radius: 6px; margin: 0.8em 0; }
+summary { cursor: pointer; padding: 0.5em 0.8em; background: #f6f8fa; font-weight: 600; }
+.badge { display: inline-block; border-radius: 1em; padding: 0 0.6em; font-size: 0.85em; font-weight: normal; color: #fff; }
+.badge.added { background: #1a7f37; }
+.badge.removed { background: #cf222e; }
+.badge.modified { background: #9a6700; }
+.badge.renamed { background: #8250df; }
+.badge.reordered { background: #57606a; }
+.stat-added { color: #1a7f37; }
+.stat-removed { color: #cf222e; }
+ul.fields { margin: 0.5em 0; }
+ul.notes { margin: 0.5em 0; color: #57606a; }
+table.diff { border-collapse: collapse; width: 100%; font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 0.85em; }
+table.diff td { padding: 0 0.5em; white-space: pre-wrap; word-break: break-all; vertical-align: top; }
+table.diff td.number { color: #6e7781; text-align: right; width: 1%; white-space: nowrap; user-select: none; }
+table.diff tr.hunk td { background: #ddf4ff; color: #57606a; }
+table.diff .added { background: #e6ffec; }
+table.diff .removed { background: #ffebe9; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<table class="summary">
+<tr><th>Changed resources</th><td>{{ .Summary.Resources }}</td></tr>
+<tr><th>Added</th><td>{{ .Summary.Added }}</td></tr>
+<tr><th>Removed</th><td>{{ .Summary.Removed }}</td></tr>
+<tr><th>Modified</th><td>{{ .Summary.Modified }}</td></tr>
+<tr><th>Renamed</th><td>{{ .Summary.Renamed }}</td></tr>
+{{- if .Summary.Reordered }}
+<tr><th>Reordered</th><td>{{ .Summary.Reordered }}</td></tr>
+{{- end }}
+<tr><th>Lines</th><td><span class="stat-added">+{{ .Summary.LinesAdded }}</span> <span class="stat-removed">-{{ .Summary.LinesRemoved }}</span></td></tr>
+{{- if .Summary.FilteredOut }}
+<tr><th>Filtered out</th><td>{{ .Summary.FilteredOut }}</td></tr>
+{{- end }}
+{{- if .Summary.DefaultsOnly }}
+<tr><th>Defaults only</th><td>{{ .Summary.DefaultsOnly }}</td></tr>
+{{- end }}
+</table>
+<h2>Resources</h2>
+<ul class="index">
+{{- range .Namespaces }}
+<li>{{ if .Name }}{{ .Name }}{{ else }}<em>cluster-scoped</em>{{ end }}
+<ul class="index">
+{{- range .Kinds }}
+<li>{{ .Name }}
+<ul class="index">
+{{- range .Resources }}
+<li><a href="#{{ .Anchor }}">{{ .ID.Name }}</a> <span class="badge {{ .ChangeType }}">{{ .ChangeType }}</span> <span class="stat-added">+{{ .LinesAdded }}</span> <span class="stat-removed">-{{ .LinesRemoved }}</span></li>
+{{- end }}
+</ul>
+</li>
+{{- end }}
+</ul>
+</li>
+{{- end }}
+</ul>
+<h2>Diffs</h2>
+{{- range .Resources }}
+<details id="{{ .Anchor }}">
+<summary>{{ .Header }}</summary>
+{{- if .Notes }}
+<ul class="notes">
+{{- range .Notes }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- if .FieldChanges }}
+<ul class="fields">
+{{- range .FieldChanges }}
+<li><code>{{ .String }}</code></li>
+{{- end }}
+</ul>
+{{- end }}
+<table class="diff">
+{{- range .Hunks }}
+{{- if .Header }}
+<tr class="hunk"><td colspan="4">{{ .Header }}</td></tr>
+{{- end }}
+{{- if $.SideBySide }}
+{{- range .Pairs }}
+<tr><td class="number">{{ if .HasOld }}{{ .OldNumber }}{{ end }}</td><td class="{{ if and .Changed .HasOld }}removed{{ end }}">{{ .OldText }}</td><td class="number">{{ if .HasNew }}{{ .NewNumber }}{{ end }}</td><td class="{{ if and .Changed .HasNew }}added{{ end }}">{{ .NewText }}</td></tr>
+{{- end }}
+{{- else }}
+{{- range .Lines }}
+<tr class="{{ .Class }}"><td class="number">{{ if .OldNumber }}{{ .OldNumber }}{{ end }}</td><td class="number">{{ if .NewNumber }}{{ .NewNumber }}{{ end }}</td><td>{{ .Prefix }}</td><td>{{ .Text }}</td></tr>
+{{- end }}
+{{- end }}
+{{- end }}
+</table>
+</details>
+{{- end }}
+{{- if .DefaultsOnly }}
+<h2>Defaults Only</h2>
+<ul class="index">
+{{- range .DefaultsOnly }}
+<li>{{ .Header }}
+<ul class="fields">
+{{- range .FieldChanges }}
+<li><code>{{ .String }}</code></li>
+{{- end }}
+</ul>
+</li>
+{{- end }}
+</ul>
+{{- end }}
+<script>
+function openLinkedDiff() {
+  var element = location.hash && document.getElementById(location.hash.substring(1));
+  if (element) { element.open = true; }
+}
+window.addEventListener("hashchange", openLinkedDiff);
+openLinkedDiff();
+</script>
+</body>
+</html>
+`))
+
+// Prints the diffs of the given report as standalone HTML report, containing summary counts, a resource index grouped by
+// namespace and kind as well as collapsible per-resource diffs. Unchanged resources are skipped.
+func PrintHtmlReport(diffReport *DiffReport, options *HtmlReportOptions, output io.Writer) error {
+	report := htmlReport{
+		Title:      cmp.Or(options.Title, "Kustomize Diff"),
+		Summary:    diffReport.Summary(),
+		SideBySide: options.SideBySide,
+	}
+
+	for _, diff := range diffReport.Diffs {
+		if diff.ChangeType == ChangeTypeUnchanged {
+			continue
+		}
+
+		resource := htmlResource{
+			Anchor:       "resource-" + strconv.Itoa(len(report.Resources)+1),
+			Header:       diff.Header(),
+			ID:           diff.ID,
+			ChangeType:   diff.ChangeType,
+			LinesAdded:   diff.LinesAdded,
+			LinesRemoved: diff.LinesRemoved,
+			Notes:        diff.Notes,
+			Hunks:        createHtmlHunks(&diff, options),
+		}
+
+		if options.FieldChanges {
+			resource.FieldChanges = diff.FieldChanges
+		}
+
+		report.Resources = append(report.Resources, resource)
+		report.Namespaces = addResourceToHtmlIndex(report.Namespaces, resource)
+	}
+
+	for _, change := range diffReport.DefaultsOnly {
+		report.DefaultsOnly = append(report.DefaultsOnly, htmlDefaultsOnlyResource{Header: change.Header(), FieldChanges: change.FieldChanges})
+	}
+
+	// The index is sorted by namespace and kind, while the resources keep the order of the given diffs.
+	slices.SortFunc(report.Namespaces, func(a, b htmlNamespace) int { return cmp.Compare(a.Name, b.Name) })
+	for _, namespace := range report.Namespaces {
+		slices.SortFunc(namespace.Kinds, func(a, b htmlKind) int { return cmp.Compare(a.Name, b.Name) })
+	}
+
+	err := htmlReportTemplate.Execute(output, report)
+	if err != nil {
+		return errors.Join(errors.New("Rendering the HTML report failed."), err)
+	}
+
+	return nil
+}
+
+// Adds the given resource to the namespace and kind of the resource index.
+func addResourceToHtmlIndex(namespaces []htmlNamespace, resource htmlResource) []htmlNamespace {
+	namespaceIndex := slices.IndexFunc(namespaces, func(n htmlNamespace) bool { return n.Name == resource.ID.Namespace })
+	if namespaceIndex < 0 {
+		namespaces = append(namespaces, htmlNamespace{Name: resource.ID.Namespace})
+		namespaceIndex = len(namespaces) - 1
+	}
+
+	namespace := &namespaces[namespaceIndex]
+	kindIndex := slices.IndexFunc(namespace.Kinds, func(k htmlKind) bool { return k.Name == resource.ID.Kind })
+	if kindIndex < 0 {
+		namespace.Kinds = append(namespace.Kinds, htmlKind{Name: resource.ID.Kind})
+		kindIndex = len(namespace.Kinds) - 1
+	}
+
+	namespace.Kinds[kindIndex].Resources = append(namespace.Kinds[kindIndex].Resources, resource)
+
+	return namespaces
+}
+
+// Creates the hunks of the given diff for the HTML report.
+func createHtmlHunks(diff *ManifestDiff, options *HtmlReportOptions) []htmlHunk {
+	hunks := []DiffHunk{{OldStart: 1, NewStart: 1, Lines: diff.Lines}}
+	if options.ContextLines >= 0 {
+		hunks = CreateDiffHunks(diff.Lines, options.ContextLines)
+	}
+
+	var result []htmlHunk
+	for _, hunk := range hunks {
+		htmlHunk := htmlHunk{}
+		if options.ContextLines >= 0 {
+			htmlHunk.Header = hunk.Header()
+		}
+
+		if options.SideBySide {
+			htmlHunk.Pairs = PairDiffLines(hunk.Lines, hunk.OldStart, hunk.NewStart)
+			result = append(result, htmlHunk)
+
+			continue
+		}
+
+		oldNumber, newNumber := max(hunk.OldStart, 1), max(hunk.NewStart, 1)
+		for _, line := range hunk.Lines {
+			htmlLine := htmlLine{Prefix: line.Operation.Prefix(), Text: line.Text}
+
+			switch line.Operation {
+			case DiffOperationAdded:
+				htmlLine.Class, htmlLine.NewNumber = "added", newNumber
+			case DiffOperationRemoved:
+				htmlLine.Class, htmlLine.OldNumber = "removed", oldNumber
+			default:
+				htmlLine.OldNumber, htmlLine.NewNumber = oldNumber, newNumber
+			}
+
+			oldNumber, newNumber = advanceLineNumbers(line, oldNumber, newNumber)
+			htmlHunk.Lines = append(htmlHunk.Lines, htmlLine)
+		}
+
+		result = append(result, htmlHunk)
+	}
+
+	return result
+}
@@ -0,0 +1,84 @@
+package kubernetes
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintHtmlReportContainsSummaryIndexAndDiffs(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "backend", Namespace: "my-namespace", Content: "spec:\n  replicas: 1\n"}
+	newManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "backend", Namespace: "my-namespace", Content: "spec:\n  replicas: 2\n"}
+	namespace := Manifest{ApiVersion: "v1", Kind: "Namespace", Name: "my-namespace", Content: "kind: Namespace\n"}
+	diffs := []ManifestDiff{
+		*CreateDiffForManifests(&oldManifest, &newManifest),
+		*CreateDiffForManifests(&Manifest{}, &namespace),
+	}
+
+	output := new(bytes.Buffer)
+	err := PrintHtmlReport(&DiffReport{Diffs: diffs}, &HtmlReportOptions{ContextLines: 3}, output)
+	if err != nil {
+		t.Fatal("Printing the HTML report should not fail.", err)
+	}
+
+	report := output.String()
+
+	expectedFragments := []string{
+		"<!DOCTYPE html>",
+		"<tr><th>Changed resources</th><td>2</td></tr>",
+		"<li>my-namespace",
+		"<em>cluster-scoped</em>",
+		`<a href="#resource-1">backend</a>`,
+		`<details id="resource-1">`,
+		"<summary>Deployment my-namespace/backend (modified, &#43;1 -1)</summary>",
+		`<tr class="hunk"><td colspan="4">@@ -1,2 &#43;1,2 @@</td></tr>`,
+		`<tr class="removed"><td class="number">2</td><td class="number"></td><td>-</td><td>  replicas: 1</td></tr>`,
+		`<tr class="added"><td class="number"></td><td class="number">2</td><td>&#43;</td><td>  replicas: 2</td></tr>`,
+	}
+
+	for _, fragment := range expectedFragments {
+		if !strings.Contains(report, fragment) {
+			t.Fatal("The HTML report should contain '" + fragment + "'. Report:\n" + report)
+		}
+	}
+
+	if strings.Contains(report, "<link") || strings.Contains(report, "src=") {
+		t.Fatal("The HTML report should not reference external resources.")
+	}
+}
+
+func TestPrintHtmlReportEscapesContent(t *testing.T) {
+	manifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "config", Content: "data:\n  script: <script>alert(1)</script>\n"}
+	diffs := []ManifestDiff{*CreateDiffForManifests(&Manifest{}, &manifest)}
+
+	output := new(bytes.Buffer)
+	err := PrintHtmlReport(&DiffReport{Diffs: diffs}, &HtmlReportOptions{ContextLines: -1, SideBySide: true}, output)
+	if err != nil {
+		t.Fatal("Printing the HTML report should not fail.", err)
+	}
+
+	if strings.Contains(output.String(), "<script>alert(1)</script>") || !strings.Contains(output.String(), "&lt;script&gt;alert(1)&lt;/script&gt;") {
+		t.Fatal("The HTML report should escape manifest content. Report:\n" + output.String())
+	}
+}
+
+func TestPrintHtmlReportUsesUniqueAnchors(t *testing.T) {
+	first := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "app.config", Content: "a: 1\n"}
+	second := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "app-config", Content: "a: 1\n"}
+	diffs := []ManifestDiff{
+		*CreateDiffForManifests(&Manifest{}, &first),
+		*CreateDiffForManifests(&Manifest{}, &second),
+	}
+
+	output := new(bytes.Buffer)
+	if err := PrintHtmlReport(&DiffReport{Diffs: diffs}, &HtmlReportOptions{ContextLines: 3}, output); err != nil {
+		t.Fatal("Printing the HTML report should not fail.", err)
+	}
+
+	report := output.String()
+	for _, fragment := range []string{`<details id="resource-1">`, `<details id="resource-2">`, `<a href="#resource-2">app-config</a>`} {
+		if !strings.Contains(report, fragment) {
+			t.Fatal("Resources with similar names should have distinct anchors, missing '" + fragment + "'. Report:\n" + report)
+		}
+	}
+}
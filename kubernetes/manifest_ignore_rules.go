@@ -0,0 +1,221 @@
+package kubernetes
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A rule which removes matching fields from manifests before they are compared.
+// If the kind is set, the rule only applies to manifests of this kind.
+type IgnoreRule struct {
+	Kind     string
+	Segments []pathSegment
+}
+
+// A segment of a field path. Unquoted keys may contain wildcards (see matchGlob), quoted keys are matched exactly.
+// Index segments match list items by position or all list items for '*', just like the unquoted key '*'.
+type pathSegment struct {
+	Pattern string
+	Quoted  bool
+	Index   bool
+}
+
+// Parses an ignore rule of the form '[Kind:]path', e.g. 'metadata.annotations["checksum/config"]',
+// 'Deployment:spec.replicas' or 'spec.template.spec.containers[*].env'.
+func ParseIgnoreRule(rule string) (IgnoreRule, error) {
+	kind, fieldPath := "", rule
+	if index := strings.Index(rule, ":"); index >= 0 && !strings.ContainsAny(rule[:index], ".[") {
+		kind, fieldPath = rule[:index], rule[index+1:]
+	}
+
+	segments, err := parseFieldPath(fieldPath)
+	if err != nil {
+		return IgnoreRule{}, errors.Join(errors.New("The ignore rule '"+rule+"' is invalid."), err)
+	}
+
+	return IgnoreRule{Kind: kind, Segments: segments}, nil
+}
+
+// Parses a JSONPath-like field path into its segments.
+func parseFieldPath(fieldPath string) ([]pathSegment, error) {
+	var segments []pathSegment
+
+	for i := 0; i < len(fieldPath); {
+		switch fieldPath[i] {
+		case '.':
+			if i == 0 || i == len(fieldPath)-1 || fieldPath[i+1] == '.' || fieldPath[i+1] == '[' {
+				return nil, errors.New("Unexpected '.' at position " + strconv.Itoa(i) + ".")
+			}
+
+			i++
+		case '[':
+			if i+1 < len(fieldPath) && (fieldPath[i+1] == '"' || fieldPath[i+1] == '\'') {
+				// Quoted keys may contain ']', so the end is determined by the closing quote.
+				closing := strings.Index(fieldPath[i+2:], string(fieldPath[i+1])+"]")
+				if closing < 0 {
+					return nil, errors.New("Missing closing quote for key at position " + strconv.Itoa(i) + ".")
+				}
+
+				segments = append(segments, pathSegment{Pattern: fieldPath[i+2 : i+2+closing], Quoted: true})
+				i += closing + 4
+
+				continue
+			}
+
+			end := strings.Index(fieldPath[i:], "]")
+			if end < 0 {
+				return nil, errors.New("Missing ']' for '[' at position " + strconv.Itoa(i) + ".")
+			}
+
+			index := fieldPath[i+1 : i+end]
+			if _, err := strconv.Atoi(index); index != "*" && err != nil {
+				return nil, errors.New("The index '" + index + "' must be a number or '*'.")
+			}
+
+			segments = append(segments, pathSegment{Pattern: index, Index: true})
+			i += end + 1
+		default:
+			end := strings.IndexAny(fieldPath[i:], ".[")
+			if end < 0 {
+				end = len(fieldPath) - i
+			}
+
+			segments = append(segments, pathSegment{Pattern: fieldPath[i : i+end]})
+			i += end
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, errors.New("The path must not be empty.")
+	}
+
+	return segments, nil
+}
+
+// Removes all fields matching the rule from the given YAML document. Returns true if any field was removed.
+func (r IgnoreRule) Apply(manifest *Manifest, document *yaml.Node) bool {
+	if r.Kind != "" && r.Kind != manifest.Kind {
+		return false
+	}
+
+	removed := false
+	for _, node := range document.Content {
+		removed = removeMatchingFields(node, r.Segments) || removed
+	}
+
+	return removed
+}
+
+// Removes all fields matching the given path segments from the given node. Returns true if any field was removed.
+func removeMatchingFields(node *yaml.Node, segments []pathSegment) bool {
+	segment, last := segments[0], len(segments) == 1
+	removed := false
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		var content []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			if segment.matchesKey(key.Value) {
+				if last {
+					removed = true
+					continue
+				}
+
+				if removeMatchingFields(value, segments[1:]) {
+					removed = true
+
+					// Collections which became empty are removed as well, as they would otherwise show up as change.
+					if isEmptyCollection(value) {
+						continue
+					}
+				}
+			}
+
+			content = append(content, key, value)
+		}
+
+		node.Content = content
+	case yaml.SequenceNode:
+		var content []*yaml.Node
+		for i, item := range node.Content {
+			if segment.matchesIndex(i) {
+				if last {
+					removed = true
+					continue
+				}
+
+				if removeMatchingFields(item, segments[1:]) {
+					removed = true
+
+					if isEmptyCollection(item) {
+						continue
+					}
+				}
+			}
+
+			content = append(content, item)
+		}
+
+		node.Content = content
+	}
+
+	return removed
+}
+
+// Determines whether the given node is a mapping or sequence without content.
+func isEmptyCollection(node *yaml.Node) bool {
+	return (node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode) && len(node.Content) == 0
+}
+
+// Determines whether the segment matches the given mapping key.
+func (s pathSegment) matchesKey(key string) bool {
+	if s.Index {
+		return s.Pattern == "*"
+	}
+
+	if s.Quoted {
+		return s.Pattern == key
+	}
+
+	return matchGlob(s.Pattern, key)
+}
+
+// Determines whether the segment matches the given sequence index.
+func (s pathSegment) matchesIndex(index int) bool {
+	return (!s.Quoted && s.Pattern == "*") || (s.Index && s.Pattern == strconv.Itoa(index))
+}
+
+// Matches the given value against the given glob pattern, where '*' matches any sequence of characters (including
+// '/' and '.', e.g. in 'checksum/config') and '?' matches a single character. All other characters match literally.
+func matchGlob(pattern string, value string) bool {
+	patternRunes, valueRunes := []rune(pattern), []rune(value)
+
+	// After a mismatch, the last '*' is extended by one character and matching continues behind it.
+	p, v, star, starMatch := 0, 0, -1, 0
+	for v < len(valueRunes) {
+		switch {
+		case p < len(patternRunes) && patternRunes[p] == '*':
+			star, starMatch = p, v
+			p++
+		case p < len(patternRunes) && (patternRunes[p] == '?' || patternRunes[p] == valueRunes[v]):
+			p++
+			v++
+		case star >= 0:
+			starMatch++
+			p, v = star+1, starMatch
+		default:
+			return false
+		}
+	}
+
+	for p < len(patternRunes) && patternRunes[p] == '*' {
+		p++
+	}
+
+	return p == len(patternRunes)
+}
@@ -0,0 +1,156 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseIgnoreRuleParsesKindAndPath(t *testing.T) {
+	rule, err := ParseIgnoreRule(`Deployment:metadata.annotations["checksum/config"]`)
+	if err != nil {
+		t.Fatal("Parsing the ignore rule should not fail.", err)
+	}
+
+	expectedSegments := []pathSegment{{Pattern: "metadata"}, {Pattern: "annotations"}, {Pattern: "checksum/config", Quoted: true}}
+	if rule.Kind != "Deployment" || len(rule.Segments) != len(expectedSegments) {
+		t.Fatal("The ignore rule should contain kind and path segments.", rule)
+	}
+
+	for i := range expectedSegments {
+		if rule.Segments[i] != expectedSegments[i] {
+			t.Fatal("The ignore rule should contain kind and path segments.", rule)
+		}
+	}
+}
+
+func TestParseIgnoreRuleParsesIndexesAndWildcards(t *testing.T) {
+	rule, err := ParseIgnoreRule("spec.template.spec.containers[*].env[0].value")
+	if err != nil {
+		t.Fatal("Parsing the ignore rule should not fail.", err)
+	}
+
+	if rule.Kind != "" || len(rule.Segments) != 8 || !rule.Segments[4].Index || rule.Segments[4].Pattern != "*" || !rule.Segments[6].Index || rule.Segments[7].Pattern != "value" {
+		t.Fatal("The ignore rule should contain index segments.", rule)
+	}
+}
+
+func TestParseIgnoreRuleRejectsInvalidPaths(t *testing.T) {
+	for _, rule := range []string{"", "metadata..name", "metadata.", "spec.containers[a]", `metadata.annotations["foo]`, "spec.containers[0"} {
+		if _, err := ParseIgnoreRule(rule); err == nil {
+			t.Fatal("The ignore rule '" + rule + "' should not be parsed successfully.")
+		}
+	}
+}
+
+func TestIgnoreRuleRemovesMatchingFields(t *testing.T) {
+	content := "kind: Deployment\nmetadata:\n  name: backend\n  labels:\n    app: backend\n    build-timestamp: \"1234\"\n    build-id: \"42\"\nspec:\n  replicas: 1\n"
+	expectedContent := "kind: Deployment\nmetadata:\n  name: backend\n  labels:\n    app: backend\nspec:\n  replicas: 1\n"
+
+	rule, _ := ParseIgnoreRule("metadata.labels.build-*")
+	result := applyIgnoreRule(t, rule, "Deployment", content)
+
+	if result != expectedContent {
+		t.Fatal("Fields matching the ignore rule should be removed. Result:\n" + result)
+	}
+}
+
+func TestIgnoreRuleRemovesEmptiedCollections(t *testing.T) {
+	content := "kind: Deployment\nmetadata:\n  name: backend\n  annotations:\n    checksum/config: abc\n"
+	expectedContent := "kind: Deployment\nmetadata:\n  name: backend\n"
+
+	rule, _ := ParseIgnoreRule(`metadata.annotations["checksum/config"]`)
+	result := applyIgnoreRule(t, rule, "Deployment", content)
+
+	if result != expectedContent {
+		t.Fatal("Collections which became empty should be removed. Result:\n" + result)
+	}
+}
+
+func TestIgnoreRuleWildcardsMatchPrefixedKeys(t *testing.T) {
+	content := "kind: Deployment\nmetadata:\n  name: backend\n  annotations:\n    checksum/config: abc\n    checksum/secret: def\n    example.com/build-time: \"1234\"\n    owner: team\n"
+	expectedContent := "kind: Deployment\nmetadata:\n  name: backend\n  annotations:\n    owner: team\n"
+
+	checksumRule, _ := ParseIgnoreRule("metadata.annotations.checksum*")
+	buildTimeRule, _ := ParseIgnoreRule("metadata.annotations.*build-time")
+	result := applyIgnoreRule(t, checksumRule, "Deployment", content)
+	result = applyIgnoreRule(t, buildTimeRule, "Deployment", result)
+
+	if result != expectedContent {
+		t.Fatal("Wildcards should match keys with prefixes. Result:\n" + result)
+	}
+}
+
+func TestIgnoreRuleQuotedKeysAreMatchedExactly(t *testing.T) {
+	content := "kind: Deployment\nmetadata:\n  name: backend\n  annotations:\n    example.com/*: \"1\"\n    example.com/build-time: \"1234\"\n"
+	expectedContent := "kind: Deployment\nmetadata:\n  name: backend\n  annotations:\n    example.com/build-time: \"1234\"\n"
+
+	rule, _ := ParseIgnoreRule(`metadata.annotations["example.com/*"]`)
+	result := applyIgnoreRule(t, rule, "Deployment", content)
+
+	if result != expectedContent {
+		t.Fatal("Quoted keys should not contain wildcards. Result:\n" + result)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		value    string
+		expected bool
+	}{
+		{"checksum*", "checksum/config", true},
+		{"*/build-time", "example.com/build-time", true},
+		{"build-?d", "build-id", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b", "axxbyyc", false},
+		{"build-*", "app", false},
+		{"", "", true},
+		{"*", "", true},
+	}
+
+	for _, test := range tests {
+		if matchGlob(test.pattern, test.value) != test.expected {
+			t.Fatal("Matching '"+test.value+"' against '"+test.pattern+"' returned an unexpected result.", test.expected)
+		}
+	}
+}
+
+func TestIgnoreRuleRemovesFieldsOfListItems(t *testing.T) {
+	content := "kind: Pod\nspec:\n  containers:\n    - name: a\n      image: a:1\n    - name: b\n      image: b:1\n"
+	expectedContent := "kind: Pod\nspec:\n  containers:\n    - name: a\n    - name: b\n"
+
+	rule, _ := ParseIgnoreRule("spec.containers[*].image")
+	result := applyIgnoreRule(t, rule, "Pod", content)
+
+	if result != expectedContent {
+		t.Fatal("Fields of all list items should be removed. Result:\n" + result)
+	}
+}
+
+func TestIgnoreRuleIsScopedToKind(t *testing.T) {
+	content := "kind: Service\nspec:\n  replicas: 1\n"
+
+	rule, _ := ParseIgnoreRule("Deployment:spec.replicas")
+	result := applyIgnoreRule(t, rule, "Service", content)
+
+	if result != content {
+		t.Fatal("Ignore rules for other kinds should not remove fields. Result:\n" + result)
+	}
+}
+
+func applyIgnoreRule(t *testing.T, rule IgnoreRule, kind string, content string) string {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		t.Fatal("Parsing the test manifest should not fail.", err)
+	}
+
+	rule.Apply(&Manifest{Kind: kind}, &document)
+
+	result, err := encodeYamlDocument(&document)
+	if err != nil {
+		t.Fatal("Encoding the test manifest should not fail.", err)
+	}
+
+	return result
+}
@@ -0,0 +1,154 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// The version of the JSON document printed by PrintJson. It is increased whenever the structure changes incompatibly.
+const JsonDocumentVersion = 1
+
+// The JSON document printed by PrintJson.
+type jsonDocument struct {
+	Version      int                        `json:"version"`
+	Resources    []jsonResource             `json:"resources"`
+	DefaultsOnly []jsonDefaultsOnlyResource `json:"defaultsOnly"`
+	Totals       jsonTotals                 `json:"totals"`
+}
+
+// A changed resource within the JSON document. The old and new content are the source contents of the manifests,
+// so that they are not canonicalized by a semantic comparison.
+type jsonResource struct {
+	ID           jsonResourceID    `json:"id"`
+	Header       string            `json:"header"`
+	ChangeType   ChangeType        `json:"changeType"`
+	OldContent   string            `json:"oldContent"`
+	NewContent   string            `json:"newContent"`
+	Diff         string            `json:"diff"`
+	LinesAdded   int               `json:"linesAdded"`
+	LinesRemoved int               `json:"linesRemoved"`
+	FieldChanges []jsonFieldChange `json:"fieldChanges"`
+	Notes        []string          `json:"notes"`
+}
+
+// A resource which only differs in default values within the JSON document.
+type jsonDefaultsOnlyResource struct {
+	ID           jsonResourceID    `json:"id"`
+	FieldChanges []jsonFieldChange `json:"fieldChanges"`
+}
+
+// A changed field of a resource within the JSON document.
+type jsonFieldChange struct {
+	Path               string     `json:"path"`
+	ChangeType         ChangeType `json:"changeType"`
+	OldValue           any        `json:"oldValue"`
+	NewValue           any        `json:"newValue"`
+	OldNormalizedValue string     `json:"oldNormalizedValue,omitempty"`
+	NewNormalizedValue string     `json:"newNormalizedValue,omitempty"`
+}
+
+// The identity of a resource within the JSON document.
+type jsonResourceID struct {
+	Group      string `json:"group"`
+	Version    string `json:"version"`
+	ApiVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+}
+
+// The aggregate totals within the JSON document.
+type jsonTotals struct {
+	Resources    int `json:"resources"`
+	Added        int `json:"added"`
+	Removed      int `json:"removed"`
+	Modified     int `json:"modified"`
+	Renamed      int `json:"renamed"`
+	Reordered    int `json:"reordered"`
+	LinesAdded   int `json:"linesAdded"`
+	LinesRemoved int `json:"linesRemoved"`
+	FilteredOut  int `json:"filteredOut"`
+	DefaultsOnly int `json:"defaultsOnly"`
+}
+
+// Prints the diffs of the given report as versioned JSON document for machine consumption. Unchanged resources are skipped.
+func PrintJson(report *DiffReport, output io.Writer) error {
+	summary := report.Summary()
+
+	document := jsonDocument{
+		Version:      JsonDocumentVersion,
+		Resources:    []jsonResource{},
+		DefaultsOnly: []jsonDefaultsOnlyResource{},
+		Totals: jsonTotals{
+			Resources:    summary.Resources,
+			Added:        summary.Added,
+			Removed:      summary.Removed,
+			Modified:     summary.Modified,
+			Renamed:      summary.Renamed,
+			Reordered:    summary.Reordered,
+			LinesAdded:   summary.LinesAdded,
+			LinesRemoved: summary.LinesRemoved,
+			FilteredOut:  summary.FilteredOut,
+			DefaultsOnly: summary.DefaultsOnly,
+		},
+	}
+
+	for _, diff := range report.Diffs {
+		if diff.ChangeType == ChangeTypeUnchanged {
+			continue
+		}
+
+		document.Resources = append(document.Resources, jsonResource{
+			ID:           createJsonResourceID(diff.ID),
+			Header:       diff.Header(),
+			ChangeType:   diff.ChangeType,
+			OldContent:   diff.OldManifest.Source(),
+			NewContent:   diff.NewManifest.Source(),
+			Diff:         diff.Diff,
+			LinesAdded:   diff.LinesAdded,
+			LinesRemoved: diff.LinesRemoved,
+			FieldChanges: createJsonFieldChanges(diff.FieldChanges),
+			Notes:        append([]string{}, diff.Notes...),
+		})
+	}
+
+	for _, change := range report.DefaultsOnly {
+		document.DefaultsOnly = append(document.DefaultsOnly, jsonDefaultsOnlyResource{
+			ID:           createJsonResourceID(change.ID),
+			FieldChanges: createJsonFieldChanges(change.FieldChanges),
+		})
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(document)
+	if err != nil {
+		return errors.Join(errors.New("Encoding diffs as JSON failed."), err)
+	}
+
+	return nil
+}
+
+// Creates the identity of the given resource within the JSON document.
+func createJsonResourceID(id ResourceID) jsonResourceID {
+	return jsonResourceID{
+		Group:      id.Group,
+		Version:    id.Version,
+		ApiVersion: id.ApiVersion(),
+		Kind:       id.Kind,
+		Namespace:  id.Namespace,
+		Name:       id.Name,
+	}
+}
+
+// Creates the field changes within the JSON document. The result is never nil, so that it is encoded as array.
+func createJsonFieldChanges(changes []FieldChange) []jsonFieldChange {
+	result := []jsonFieldChange{}
+	for _, change := range changes {
+		result = append(result, jsonFieldChange(change))
+	}
+
+	return result
+}
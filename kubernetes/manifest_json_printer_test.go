@@ -0,0 +1,134 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestPrintJsonProducesVersionedDocument(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "backend", Namespace: "my-namespace", Content: "spec:\n  replicas: 1\n"}
+	newManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "backend", Namespace: "my-namespace", Content: "spec:\n  replicas: 2\n"}
+	diffs := []ManifestDiff{
+		*CreateDiffForManifests(&oldManifest, &newManifest),
+		*CreateDiffForManifests(&oldManifest, &oldManifest),
+	}
+
+	output := new(bytes.Buffer)
+	err := PrintJson(&DiffReport{Diffs: diffs}, output)
+	if err != nil {
+		t.Fatal("Printing JSON should not fail.", err)
+	}
+
+	var document map[string]any
+	err = json.Unmarshal(output.Bytes(), &document)
+	if err != nil {
+		t.Fatal("Printed JSON should be valid.", err)
+	}
+
+	if document["version"] != float64(JsonDocumentVersion) {
+		t.Fatal("Printed JSON should contain the document version.", document)
+	}
+
+	resources := document["resources"].([]any)
+	if len(resources) != 1 {
+		t.Fatal("Printed JSON should only contain changed resources.", resources)
+	}
+
+	resource := resources[0].(map[string]any)
+	id := resource["id"].(map[string]any)
+	if id["kind"] != "Deployment" || id["apiVersion"] != "apps/v1" || id["namespace"] != "my-namespace" || id["name"] != "backend" {
+		t.Fatal("Printed JSON should contain the resource identity.", id)
+	}
+
+	if resource["changeType"] != "modified" || resource["oldContent"] != oldManifest.Content || resource["newContent"] != newManifest.Content {
+		t.Fatal("Printed JSON should contain change type and contents.", resource)
+	}
+
+	if resource["diff"] != " spec:\n-  replicas: 1\n+  replicas: 2" {
+		t.Fatal("Printed JSON should contain the diff.", resource)
+	}
+
+	if notes, ok := resource["notes"].([]any); !ok || len(notes) != 0 {
+		t.Fatal("Printed JSON should contain an empty list of notes.", resource)
+	}
+
+	totals := document["totals"].(map[string]any)
+	if totals["resources"] != float64(1) || totals["modified"] != float64(1) || totals["linesAdded"] != float64(1) || totals["linesRemoved"] != float64(1) {
+		t.Fatal("Printed JSON should contain the totals.", totals)
+	}
+}
+
+func TestPrintJsonWithoutDiffsContainsEmptyResourceList(t *testing.T) {
+	output := new(bytes.Buffer)
+	err := PrintJson(&DiffReport{}, output)
+	if err != nil {
+		t.Fatal("Printing JSON should not fail.", err)
+	}
+
+	if !bytes.Contains(output.Bytes(), []byte(`"resources": []`)) {
+		t.Fatal("Printed JSON should contain an empty resource list if there are no diffs. JSON:\n" + output.String())
+	}
+}
+
+func TestPrintJsonContainsFieldChanges(t *testing.T) {
+	diff := ManifestDiff{
+		ID:           ResourceID{Version: "v1", Kind: "Service", Name: "web"},
+		ChangeType:   ChangeTypeModified,
+		OldManifest:  &Manifest{},
+		NewManifest:  &Manifest{},
+		FieldChanges: []FieldChange{{Path: "spec.replicas", ChangeType: ChangeTypeModified, OldValue: 1, NewValue: 2}},
+	}
+
+	output := new(bytes.Buffer)
+	err := PrintJson(&DiffReport{Diffs: []ManifestDiff{diff}}, output)
+	if err != nil {
+		t.Fatal("Printing JSON should not fail.", err)
+	}
+
+	expected := `"fieldChanges": [
+        {
+          "path": "spec.replicas",
+          "changeType": "modified",
+          "oldValue": 1,
+          "newValue": 2
+        }
+      ]`
+	if !bytes.Contains(output.Bytes(), []byte(expected)) {
+		t.Fatal("Printed JSON should contain the field changes. JSON:\n" + output.String())
+	}
+}
+
+func TestPrintJsonContainsDefaultsOnlyResources(t *testing.T) {
+	report := DiffReport{DefaultsOnly: []DefaultsOnlyChange{{
+		ID:           ResourceID{Version: "v1", Kind: "Service", Name: "web"},
+		FieldChanges: []FieldChange{{Path: "spec.type", ChangeType: ChangeTypeAdded, NewValue: "ClusterIP"}},
+	}}}
+
+	output := new(bytes.Buffer)
+	err := PrintJson(&report, output)
+	if err != nil {
+		t.Fatal("Printing JSON should not fail.", err)
+	}
+
+	var document struct {
+		DefaultsOnly []struct {
+			ID struct {
+				Kind string `json:"kind"`
+			} `json:"id"`
+			FieldChanges []map[string]any `json:"fieldChanges"`
+		} `json:"defaultsOnly"`
+		Totals struct {
+			DefaultsOnly int `json:"defaultsOnly"`
+		} `json:"totals"`
+	}
+
+	err = json.Unmarshal(output.Bytes(), &document)
+	if err != nil {
+		t.Fatal("Printed JSON should be valid.", err)
+	}
+
+	if len(document.DefaultsOnly) != 1 || document.DefaultsOnly[0].ID.Kind != "Service" || document.DefaultsOnly[0].FieldChanges[0]["path"] != "spec.type" || document.Totals.DefaultsOnly != 1 {
+		t.Fatal("Printed JSON should contain the resources which only differ in default values. JSON:\n" + output.String())
+	}
+}
@@ -0,0 +1,187 @@
+package kubernetes
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A merge key identifies the items of a list by one of their fields, e.g. containers by their name.
+// The path is matched against the end of the list location, so 'ports' matches all lists named 'ports' and
+// 'containers[*].ports' only the ports of containers. If the kind is set, the merge key only applies to manifests of this kind.
+type MergeKey struct {
+	Kind     string
+	Segments []pathSegment
+	Key      string
+}
+
+// The built-in merge keys of well-known lists of core types, based on their strategic merge patch keys.
+var DefaultMergeKeys = []MergeKey{
+	mustParseMergeKey("containers=name"),
+	mustParseMergeKey("initContainers=name"),
+	mustParseMergeKey("ephemeralContainers=name"),
+	mustParseMergeKey("containers[*].ports=containerPort"),
+	mustParseMergeKey("initContainers[*].ports=containerPort"),
+	mustParseMergeKey("env=name"),
+	mustParseMergeKey("volumes=name"),
+	mustParseMergeKey("volumeMounts=mountPath"),
+	mustParseMergeKey("volumeDevices=devicePath"),
+	mustParseMergeKey("imagePullSecrets=name"),
+	mustParseMergeKey("hostAliases=ip"),
+	mustParseMergeKey("topologySpreadConstraints=topologyKey"),
+	mustParseMergeKey("Service:spec.ports=port"),
+}
+
+// Parses a merge key of the form '[Kind:]path=key', e.g. 'volumeMounts=mountPath' or 'MyResource:spec.backends=id'.
+func ParseMergeKey(mergeKey string) (MergeKey, error) {
+	index := strings.LastIndex(mergeKey, "=")
+	if index < 0 || index == len(mergeKey)-1 {
+		return MergeKey{}, errors.New("The merge key '" + mergeKey + "' is invalid: must be of the form '[Kind:]path=key'.")
+	}
+
+	rule, err := ParseIgnoreRule(mergeKey[:index])
+	if err != nil {
+		return MergeKey{}, errors.Join(errors.New("The merge key '"+mergeKey+"' is invalid."), err)
+	}
+
+	for _, segment := range rule.Segments {
+		if segment.Index && segment.Pattern != "*" {
+			return MergeKey{}, errors.New("The merge key '" + mergeKey + "' is invalid: list items must be selected using '[*]'.")
+		}
+	}
+
+	return MergeKey{Kind: rule.Kind, Segments: rule.Segments, Key: mergeKey[index+1:]}, nil
+}
+
+// Parses the given merge key and panics if it is invalid. Only used for the built-in merge keys.
+func mustParseMergeKey(mergeKey string) MergeKey {
+	result, err := ParseMergeKey(mergeKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// Determines whether the merge key applies to the list at the given location of the given manifest.
+// The location contains the keys of all parent mappings, with '[]' for list items.
+func (k MergeKey) matches(manifest *Manifest, location []string) bool {
+	return matchesLocation(k.Kind, k.Segments, manifest, location)
+}
+
+// Determines whether the given path segments match the end of the given location of the given manifest.
+// If the kind is set, only locations within manifests of this kind are matched.
+func matchesLocation(kind string, segments []pathSegment, manifest *Manifest, location []string) bool {
+	if (kind != "" && kind != manifest.Kind) || len(segments) > len(location) {
+		return false
+	}
+
+	offset := len(location) - len(segments)
+	for i, segment := range segments {
+		if segment.Index {
+			if location[offset+i] != "[]" {
+				return false
+			}
+		} else if location[offset+i] == "[]" || !segment.matchesKey(location[offset+i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Finds the merge key for the list at the given location. Later merge keys take precedence, so that configured
+// merge keys can override the built-in ones. Returns an empty string if no merge key applies.
+func findMergeKey(mergeKeys []MergeKey, manifest *Manifest, location []string) string {
+	for i := len(mergeKeys) - 1; i >= 0; i-- {
+		if mergeKeys[i].matches(manifest, location) {
+			return mergeKeys[i].Key
+		}
+	}
+
+	return ""
+}
+
+// Aligns the items of all lists with a merge key in the old document to the order of the new document,
+// so that reordered items do not show up in the line diff. Items which only exist in the old document keep
+// their position. Returns the field changes describing the reordered lists.
+func alignListsByMergeKey(manifest *Manifest, mergeKeys []MergeKey, oldDocument *yaml.Node, newDocument *yaml.Node) []FieldChange {
+	var changes []FieldChange
+	alignNodes(manifest, mergeKeys, "", nil, documentRoot(oldDocument), documentRoot(newDocument), &changes)
+
+	return changes
+}
+
+// Aligns the lists within the given nodes at the given path and location and appends reorders to the given list.
+func alignNodes(manifest *Manifest, mergeKeys []MergeKey, path string, location []string, old *yaml.Node, new *yaml.Node, changes *[]FieldChange) {
+	if old == nil || new == nil || old.Kind != new.Kind {
+		return
+	}
+
+	switch old.Kind {
+	case yaml.MappingNode:
+		for _, key := range mappingKeys(old) {
+			alignNodes(manifest, mergeKeys, appendKeyToPath(path, key), append(slices.Clip(location), key), mappingValue(old, key), mappingValue(new, key), changes)
+		}
+	case yaml.SequenceNode:
+		itemLocation := append(slices.Clip(location), "[]")
+
+		mergeKey := findMergeKey(mergeKeys, manifest, location)
+		if mergeKey == "" || !hasUniqueMergeKey(old, mergeKey) || !hasUniqueMergeKey(new, mergeKey) {
+			for i := range min(len(old.Content), len(new.Content)) {
+				alignNodes(manifest, mergeKeys, path+"["+strconv.Itoa(i)+"]", itemLocation, old.Content[i], new.Content[i], changes)
+			}
+
+			return
+		}
+
+		if reordered := alignSequence(old, new, mergeKey); reordered != nil {
+			*changes = append(*changes, *reordered)
+			(*changes)[len(*changes)-1].Path = path
+		}
+
+		for _, value := range sequenceMergeKeyValues(new, mergeKey) {
+			oldItem, newItem := findSequenceItem(old, mergeKey, value), findSequenceItem(new, mergeKey, value)
+			alignNodes(manifest, mergeKeys, path+"["+mergeKey+"="+value+"]", itemLocation, oldItem, newItem, changes)
+		}
+	}
+}
+
+// Reorders the items of the old sequence to the order of the new sequence. Returns a field change with the
+// old and new order of the common items if they were reordered, otherwise nil.
+func alignSequence(old *yaml.Node, new *yaml.Node, mergeKey string) *FieldChange {
+	oldValues, newValues := sequenceMergeKeyValues(old, mergeKey), sequenceMergeKeyValues(new, mergeKey)
+
+	var oldOrder, newOrder []string
+	for _, value := range oldValues {
+		if slices.Contains(newValues, value) {
+			oldOrder = append(oldOrder, value)
+		}
+	}
+
+	var content []*yaml.Node
+	for _, value := range newValues {
+		if slices.Contains(oldValues, value) {
+			newOrder = append(newOrder, value)
+			content = append(content, findSequenceItem(old, mergeKey, value))
+		}
+	}
+
+	if slices.Equal(oldOrder, newOrder) {
+		return nil
+	}
+
+	// Items which were removed are inserted at their previous position.
+	for i, value := range oldValues {
+		if !slices.Contains(newValues, value) {
+			content = slices.Insert(content, min(i, len(content)), findSequenceItem(old, mergeKey, value))
+		}
+	}
+
+	old.Content = content
+
+	return &FieldChange{ChangeType: ChangeTypeReordered, OldValue: oldOrder, NewValue: newOrder}
+}
@@ -0,0 +1,86 @@
+package kubernetes
+
+import (
+	"slices"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseMergeKeyParsesKindPathAndKey(t *testing.T) {
+	mergeKey, err := ParseMergeKey("MyResource:spec.backends[*].targets=id")
+	if err != nil {
+		t.Fatal("Parsing the merge key should not fail.", err)
+	}
+
+	if mergeKey.Kind != "MyResource" || len(mergeKey.Segments) != 4 || mergeKey.Key != "id" {
+		t.Fatal("The merge key should contain kind, path and key.", mergeKey)
+	}
+}
+
+func TestParseMergeKeyRejectsInvalidMergeKeys(t *testing.T) {
+	for _, mergeKey := range []string{"", "containers", "containers=", "=name", "containers[0].ports=containerPort"} {
+		if _, err := ParseMergeKey(mergeKey); err == nil {
+			t.Fatal("The merge key '" + mergeKey + "' should not be parsed successfully.")
+		}
+	}
+}
+
+func TestFindMergeKeyUsesLastMatchingMergeKey(t *testing.T) {
+	mergeKeys := append(slices.Clone(DefaultMergeKeys), mustParseMergeKey("Pod:spec.containers=image"))
+	deployment, pod, service := &Manifest{Kind: "Deployment"}, &Manifest{Kind: "Pod"}, &Manifest{Kind: "Service"}
+
+	tests := []struct {
+		manifest *Manifest
+		location []string
+		expected string
+	}{
+		{deployment, []string{"spec", "template", "spec", "containers"}, "name"},
+		{deployment, []string{"spec", "template", "spec", "containers", "[]", "ports"}, "containerPort"},
+		{deployment, []string{"spec", "template", "spec", "containers", "[]", "volumeMounts"}, "mountPath"},
+		{pod, []string{"spec", "containers"}, "image"},
+		{service, []string{"spec", "ports"}, "port"},
+		{deployment, []string{"spec", "ports"}, ""},
+		{deployment, []string{"spec", "tolerations"}, ""},
+	}
+
+	for _, test := range tests {
+		if mergeKey := findMergeKey(mergeKeys, test.manifest, test.location); mergeKey != test.expected {
+			t.Fatalf("The merge key for %v of %s should be '%s', got '%s'.", test.location, test.manifest.Kind, test.expected, mergeKey)
+		}
+	}
+}
+
+func TestAlignListsByMergeKeyReordersOldItems(t *testing.T) {
+	oldContent := "spec:\n  containers:\n    - name: a\n    - name: removed\n    - name: b\n    - name: c\n      env:\n        - name: X\n        - name: Y\n"
+	newContent := "spec:\n  containers:\n    - name: c\n      env:\n        - name: Y\n        - name: X\n    - name: a\n    - name: b\n    - name: added\n"
+	expectedOldContent := "spec:\n  containers:\n    - name: c\n      env:\n        - name: Y\n        - name: X\n    - name: removed\n    - name: a\n    - name: b\n"
+
+	var oldDocument, newDocument yaml.Node
+	_ = yaml.Unmarshal([]byte(oldContent), &oldDocument)
+	_ = yaml.Unmarshal([]byte(newContent), &newDocument)
+
+	changes := alignListsByMergeKey(&Manifest{Kind: "Pod"}, DefaultMergeKeys, &oldDocument, &newDocument)
+
+	result, _ := encodeYamlDocument(&oldDocument)
+	if result != expectedOldContent {
+		t.Fatal("The old items should be aligned to the order of the new items. Result:\n" + result)
+	}
+
+	if len(changes) != 2 || changes[0].String() != `spec.containers: reordered ["a","b","c"] → ["c","a","b"]` || changes[1].String() != `spec.containers[name=c].env: reordered ["X","Y"] → ["Y","X"]` {
+		t.Fatal("The reordered lists should be reported.", changes)
+	}
+}
+
+func TestAlignListsByMergeKeySkipsListsWithoutUniqueKeys(t *testing.T) {
+	oldContent := "env:\n  - name: A\n  - name: A\n    value: x\n"
+	newContent := "env:\n  - name: A\n    value: x\n  - name: A\n"
+
+	var oldDocument, newDocument yaml.Node
+	_ = yaml.Unmarshal([]byte(oldContent), &oldDocument)
+	_ = yaml.Unmarshal([]byte(newContent), &newDocument)
+
+	if changes := alignListsByMergeKey(&Manifest{}, DefaultMergeKeys, &oldDocument, &newDocument); len(changes) != 0 {
+		t.Fatal("Lists without unique merge keys should not be aligned.", changes)
+	}
+}
@@ -0,0 +1,176 @@
+package kubernetes
+
+import (
+	"bytes"
+	"errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A transformation of the parsed YAML document of a manifest, which is applied before manifests are compared.
+// Returns true if the document was changed.
+type manifestTransformation func(manifest *Manifest, document *yaml.Node) bool
+
+// Creates the transformations which need to be applied to manifests for the given diff options.
+func createManifestTransformations(options *DiffOptions) []manifestTransformation {
+	var transformations []manifestTransformation
+	for _, rule := range options.IgnoreRules {
+		transformations = append(transformations, rule.Apply)
+	}
+
+	// The data is decoded before the redaction, so that decoding does not reveal redacted values.
+	if options.DecodeBase64 {
+		transformations = append(transformations, decodeBase64Data)
+	}
+
+	if options.RedactSecrets {
+		transformations = append(transformations, createSecretRedaction(nil))
+	}
+
+	return transformations
+}
+
+// The changes which were removed from manifests by the normalization, per resource.
+type normalizationResult struct {
+	Reorders map[ResourceID][]FieldChange
+	Defaults map[ResourceID][]FieldChange
+	Notes    map[ResourceID][]string
+}
+
+// Applies the given transformations to all manifests of both maps, replacing the manifest content.
+// The content after the transformations is kept as source content of the manifests, before they are canonicalized
+// for a semantic comparison.
+// Afterwards, both versions of a manifest are compared with each other using the given options: for semantic comparisons,
+// lists with a merge key in the old version are aligned to the order of the new version and resource quantities and durations
+// in the old version which are equivalent to the new version are replaced by the new values, if hash suffixes are collapsed,
+// references which only differ in their hash suffix are replaced by the new references and, if defaults are ignored,
+// fields with default values which are only set in one version are removed.
+// If the old or the new version of a manifest is changed, both versions are re-encoded,
+// so that they are formatted identically and only the actual changes remain.
+// Returns the reordered lists, removed default values and notes about collapsed hash suffixes per resource.
+func normalizeManifests(oldManifests *ManifestMap, newManifests *ManifestMap, transformations []manifestTransformation, options *DiffOptions) (*normalizationResult, error) {
+	result := &normalizationResult{
+		Reorders: make(map[ResourceID][]FieldChange),
+		Defaults: make(map[ResourceID][]FieldChange),
+		Notes:    make(map[ResourceID][]string),
+	}
+
+	if len(transformations) == 0 && !options.Semantic && !options.IgnoreDefaults && !options.CollapseHashSuffixes {
+		return result, nil
+	}
+
+	for _, id := range *GetUniqueResourceIDs(oldManifests, newManifests) {
+		oldManifest, oldExists := (*oldManifests)[id]
+		newManifest, newExists := (*newManifests)[id]
+
+		oldDocument, oldChanged, err := transformManifest(&oldManifest, oldExists, transformations, options.Semantic)
+		if err != nil {
+			return nil, err
+		}
+
+		newDocument, newChanged, err := transformManifest(&newManifest, newExists, transformations, options.Semantic)
+		if err != nil {
+			return nil, err
+		}
+
+		// Lists are only aligned for a semantic comparison, as the raw manifests are compared otherwise.
+		if oldExists && newExists && options.Semantic && len(options.MergeKeys) > 0 {
+			if changes := alignListsByMergeKey(&newManifest, options.MergeKeys, oldDocument, newDocument); len(changes) > 0 {
+				result.Reorders[id], oldChanged = changes, true
+			}
+		}
+
+		if oldExists && newExists && options.Semantic {
+			if alignEquivalentValues(&newManifest, options.MergeKeys, nil, documentRoot(oldDocument), documentRoot(newDocument)) {
+				oldChanged = true
+			}
+		}
+
+		if oldExists && newExists && options.CollapseHashSuffixes {
+			if notes := alignHashSuffixReferences(&newManifest, options.MergeKeys, documentRoot(oldDocument), documentRoot(newDocument)); len(notes) > 0 {
+				result.Notes[id], oldChanged = notes, true
+			}
+		}
+
+		if oldExists && newExists && options.IgnoreDefaults {
+			if changes := removeDefaultValues(&newManifest, options.MergeKeys, documentRoot(oldDocument), documentRoot(newDocument)); len(changes) > 0 {
+				result.Defaults[id], oldChanged = changes, true
+			}
+		}
+
+		if !oldChanged && !newChanged {
+			continue
+		}
+
+		if oldExists {
+			if oldManifest.Content, err = encodeYamlDocument(oldDocument); err != nil {
+				return nil, err
+			}
+
+			(*oldManifests)[id] = oldManifest
+		}
+
+		if newExists {
+			if newManifest.Content, err = encodeYamlDocument(newDocument); err != nil {
+				return nil, err
+			}
+
+			(*newManifests)[id] = newManifest
+		}
+	}
+
+	return result, nil
+}
+
+// Parses the content of the given manifest and applies the given transformations to it. If the document is changed,
+// it is encoded as source content of the manifest. Afterwards, the document is canonicalized if requested.
+// Returns the transformed document and whether any transformation changed it.
+func transformManifest(manifest *Manifest, exists bool, transformations []manifestTransformation, canonicalize bool) (*yaml.Node, bool, error) {
+	if !exists {
+		return nil, false, nil
+	}
+
+	var document yaml.Node
+	err := yaml.Unmarshal([]byte(manifest.Content), &document)
+	if err != nil {
+		return nil, false, errors.Join(errors.New("Parsing manifest '"+manifest.ID().String()+"' for normalization failed."), err)
+	}
+
+	changed := false
+	for _, transformation := range transformations {
+		changed = transformation(manifest, &document) || changed
+	}
+
+	if changed {
+		if manifest.SourceContent, err = encodeYamlDocument(&document); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// The canonicalization needs to be applied last, as the transformations may leave the document in a non-canonical state.
+	if canonicalize {
+		changed = canonicalizeDocument(manifest, &document) || changed
+	}
+
+	return &document, changed, nil
+}
+
+// Encodes the given YAML document with an indentation of two spaces.
+func encodeYamlDocument(document *yaml.Node) (string, error) {
+	buffer := new(bytes.Buffer)
+
+	encoder := yaml.NewEncoder(buffer)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(document)
+	if err != nil {
+		return "", errors.Join(errors.New("Encoding normalized manifest failed."), err)
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return "", errors.Join(errors.New("Encoding normalized manifest failed."), err)
+	}
+
+	return buffer.String(), nil
+}
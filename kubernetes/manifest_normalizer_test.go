@@ -0,0 +1,38 @@
+package kubernetes
+
+import "testing"
+
+func TestNormalizeManifestsReencodesBothVersionsIfOneIsChanged(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "v1", Kind: "Pod", Name: "backend", Content: "kind: Pod\nmetadata:\n  name: backend\n  annotations:\n    checksum/config: abc\nspec:\n  containers:\n  - name: app\n"}
+	newManifest := Manifest{ApiVersion: "v1", Kind: "Pod", Name: "backend", Content: "kind: Pod\nmetadata:\n  name: backend\nspec:\n  containers:\n  - name: app\n"}
+
+	oldManifests := ManifestMap{oldManifest.Key(): oldManifest}
+	newManifests := ManifestMap{newManifest.Key(): newManifest}
+
+	rule, _ := ParseIgnoreRule(`metadata.annotations["checksum/config"]`)
+	_, err := normalizeManifests(&oldManifests, &newManifests, []manifestTransformation{rule.Apply}, &DiffOptions{})
+	if err != nil {
+		t.Fatal("Normalizing manifests should not fail.", err)
+	}
+
+	if oldManifests[oldManifest.Key()].Content != newManifests[newManifest.Key()].Content {
+		t.Fatal("Both versions should be identical after normalization.", oldManifests, newManifests)
+	}
+}
+
+func TestNormalizeManifestsKeepsUntransformedManifests(t *testing.T) {
+	manifest := Manifest{ApiVersion: "v1", Kind: "Pod", Name: "backend", Content: "kind: Pod\nspec:\n  containers:\n  - name: app\n"}
+
+	oldManifests := ManifestMap{manifest.Key(): manifest}
+	newManifests := ManifestMap{}
+
+	rule, _ := ParseIgnoreRule("metadata.labels")
+	_, err := normalizeManifests(&oldManifests, &newManifests, []manifestTransformation{rule.Apply}, &DiffOptions{})
+	if err != nil {
+		t.Fatal("Normalizing manifests should not fail.", err)
+	}
+
+	if oldManifests[manifest.Key()].Content != manifest.Content {
+		t.Fatal("Manifests which are not changed by a transformation should keep their content.", oldManifests)
+	}
+}
@@ -2,6 +2,9 @@ package kubernetes
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -10,53 +13,191 @@ import (
 // This type is a convenience layer on top of a generic map and represents a YAML object.
 type YamlObject map[string]any
 
-// Splits the given Kustomization into individual manifests per object.
-func SplitKustomizationIntoManifests(kustomization *string) (*ManifestMap, error) {
+// Splits the given Kustomization into individual manifests per object. The Kustomization is decoded as YAML stream,
+// so that document markers with comments or trailing whitespace, document end markers ('...') and block scalars
+// containing '---' are handled correctly. Each manifest keeps the original text of its document and its starting line.
+func SplitKustomizationIntoManifests(kustomization io.Reader) (*ManifestMap, error) {
 	result := make(ManifestMap)
 
-	*kustomization = strings.ReplaceAll(*kustomization, "\r\n", "\n")
-	lines := strings.Split(*kustomization, "\n")
+	data, err := io.ReadAll(kustomization)
+	if err != nil {
+		return nil, errors.Join(errors.New("Reading Kustomization failed."), err)
+	}
 
-	var sb strings.Builder
-	for _, line := range lines {
-		if line != "---" {
-			sb.WriteString(line + "\n")
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
 
-			continue
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	var documents []yaml.Node
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Join(errors.New("Decoding Kustomization as YAML stream failed."), err)
+		}
+
+		documents = append(documents, document)
+	}
+
+	// The index is counted separately from the map size, as manifests with duplicate keys replace each other.
+	start, index := 0, 0
+	for i, document := range documents {
+		// A document ends before the start marker of the next document, unless it has an end marker.
+		end := len(lines)
+		if i+1 < len(documents) && isDocumentStartMarker(lines[documents[i+1].Line-1]) {
+			end = documents[i+1].Line - 1
+		}
+
+		// The text of an explicit document starts after its start marker, unless there is content on the same line.
+		first, inlineContent := start, ""
+		if marker := document.Line - 1; marker >= start && marker < end && isDocumentStartMarker(lines[marker]) {
+			first = marker + 1
+			if rest := strings.TrimSpace(lines[marker][3:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				first, inlineContent = marker, rest
+			}
+		}
+
+		last := end
+		if index := slices.IndexFunc(lines[first:end], isDocumentEndMarker); index >= 0 {
+			last = first + index
+			end = last + 1
 		}
 
-		if sb.Len() == 0 {
+		start = end
+
+		if isEmptyDocument(&document) {
 			continue
 		}
 
-		err := parseAndHashManifest(sb.String(), result)
+		documentLines := slices.Clone(lines[first:last])
+		if inlineContent != "" {
+			documentLines[0] = inlineContent
+		}
+
+		err := addDocumentManifests(&document, strings.Join(documentLines, "\n")+"\n", first+1, &index, result)
 		if err != nil {
 			return nil, err
 		}
+	}
+
+	return &result, nil
+}
+
+// Adds the manifest of the given document with the given content and starting line to the provided manifests map,
+// using the given index as position of the manifest and incrementing it afterwards. Lists like 'kind: List' or
+// 'ConfigMapList' are expanded into their items, so that each item is matched and diffed on its own.
+func addDocumentManifests(document *yaml.Node, content string, line int, index *int, manifests ManifestMap) error {
+	root := documentRoot(document)
+	items := listItems(root)
+	if items == nil {
+		err := parseAndAddManifest(content, line, *index, manifests)
+		if err != nil {
+			return err
+		}
+
+		*index++
 
-		sb.Reset()
+		return nil
 	}
 
-	if sb.Len() > 0 {
-		err := parseAndHashManifest(sb.String(), result)
+	for _, item := range items.Content {
+		completeListItem(root, item)
+
+		itemDocument := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{item}}
+		itemContent, err := encodeYamlDocument(itemDocument)
 		if err != nil {
-			return nil, err
+			return errors.Join(fmt.Errorf("Encoding list item starting at line %d failed.", item.Line), err)
+		}
+
+		err = addDocumentManifests(itemDocument, itemContent, item.Line, index, manifests)
+		if err != nil {
+			return err
 		}
 	}
 
-	return &result, nil
+	return nil
+}
+
+// Returns the items of the given node if it is a list, i.e. its kind ends with 'List' and it has items but no name.
+// Returns nil if the node is not a list.
+func listItems(node *yaml.Node) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	kind := mappingValue(node, "kind")
+	if kind == nil || kind.Kind != yaml.ScalarNode || !strings.HasSuffix(kind.Value, "List") {
+		return nil
+	}
+
+	if metadata := mappingValue(node, "metadata"); metadata != nil && metadata.Kind == yaml.MappingNode && mappingValue(metadata, "name") != nil {
+		return nil
+	}
+
+	items := mappingValue(node, "items")
+	if items == nil || (items.Kind != yaml.SequenceNode && items.Tag != "!!null") {
+		return nil
+	}
+
+	return items
+}
+
+// Adds the apiVersion and kind of the given typed list (e.g. 'ConfigMapList') to the given item if they are missing,
+// as the API server omits them for the items of typed lists.
+func completeListItem(list *yaml.Node, item *yaml.Node) {
+	kind := mappingValue(list, "kind").Value
+	if item.Kind != yaml.MappingNode || kind == "List" {
+		return
+	}
+
+	if mappingValue(item, "kind") == nil {
+		item.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "kind"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: strings.TrimSuffix(kind, "List")},
+		}, item.Content...)
+	}
+
+	if apiVersion := mappingValue(list, "apiVersion"); apiVersion != nil && mappingValue(item, "apiVersion") == nil {
+		item.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "apiVersion"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: apiVersion.Value},
+		}, item.Content...)
+	}
+}
+
+// Determines whether the given line is a document start marker ('---'), optionally followed by content or a comment.
+func isDocumentStartMarker(line string) bool {
+	return line == "---" || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "---\t")
+}
+
+// Determines whether the given line is a document end marker ('...'), optionally followed by a comment.
+func isDocumentEndMarker(line string) bool {
+	return line == "..." || strings.HasPrefix(line, "... ") || strings.HasPrefix(line, "...\t")
+}
+
+// Determines whether the given document is empty, e.g. if it only consists of comments.
+func isEmptyDocument(document *yaml.Node) bool {
+	root := documentRoot(document)
+
+	return root == nil || (root.Kind == yaml.ScalarNode && root.Tag == "!!null" && root.Value == "")
 }
 
-// Parses the given string as Kubernetes manifest and puts it as hash in the provided manifests map.
-func parseAndHashManifest(content string, manifests ManifestMap) error {
+// Parses the given string as Kubernetes manifest starting at the given line and puts it with its resource identity as key
+// in the provided manifests map. The index is the position of the manifest within the Kustomization.
+func parseAndAddManifest(content string, line int, index int, manifests ManifestMap) error {
 	manifest, err := parseManifest(content)
 	if err != nil {
-		return errors.Join(errors.New("Parsing manifest failed."), err)
+		return errors.Join(fmt.Errorf("Parsing manifest starting at line %d failed.", line), err)
 	}
 
-	hash := manifest.CalculateHash()
+	manifest.Index = index
+	manifest.Line = line
 
-	manifests[hash] = manifest
+	manifests[manifest.Key()] = manifest
 
 	return nil
 }
@@ -88,11 +229,13 @@ func parseManifest(content string) (Manifest, error) {
 	namespace := data.getMapValueOrDefault("metadata", make(YamlObject)).(YamlObject).getMapValueOrDefault("namespace", "").(string)
 
 	return Manifest{
-		ApiVersion: apiVersion,
-		Kind:       kind,
-		Name:       name,
-		Namespace:  namespace,
-		Content:    content,
+		ApiVersion:    apiVersion,
+		Kind:          kind,
+		Name:          name,
+		Namespace:     namespace,
+		Content:       content,
+		SourceContent: content,
+		RawContent:    content,
 	}, nil
 }
 
@@ -1,6 +1,9 @@
 package kubernetes
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestParsingSingleWordAsManifestFails(t *testing.T) {
 	manifest, err := parseManifest("mytwocents")
@@ -57,3 +60,113 @@ func TestParsingCompleteManifestYamlAsManifestSucceeds(t *testing.T) {
 		t.Fatal("Complete manifest YAML should be parsed as manifest successfully.")
 	}
 }
+
+func TestSplittingKustomizationHandlesDocumentMarkersWithCommentsAndWhitespace(t *testing.T) {
+	kustomization := "# leading comment\n--- # first\napiVersion: v1\nkind: Service\nmetadata:\n  name: first\n---   \napiVersion: v1\nkind: Service\nmetadata:\n  name: second\n...\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: third\n---\n"
+
+	manifests, err := SplitKustomizationIntoManifests(strings.NewReader(kustomization))
+	if err != nil {
+		t.Fatal("Splitting the Kustomization should not fail.", err)
+	}
+
+	if len(*manifests) != 3 {
+		t.Fatal("Splitting the Kustomization should result in three manifests.", *manifests)
+	}
+
+	second := (*manifests)[ResourceID{Kind: "Service", Name: "second"}]
+	if second.Content != "apiVersion: v1\nkind: Service\nmetadata:\n  name: second\n" || second.Line != 8 || second.Index != 1 {
+		t.Fatal("The manifest should keep the original text of its document without markers and its starting line.", second)
+	}
+
+	third := (*manifests)[ResourceID{Kind: "Service", Name: "third"}]
+	if third.Line != 14 || third.Index != 2 {
+		t.Fatal("The manifest after a document end marker should keep its starting line.", third)
+	}
+}
+
+func TestSplittingKustomizationKeepsDocumentMarkersInBlockScalars(t *testing.T) {
+	kustomization := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  front-matter.md: |\n    ---\n    title: Example\n    ---\n"
+
+	manifests, err := SplitKustomizationIntoManifests(strings.NewReader(kustomization))
+	if err != nil {
+		t.Fatal("Splitting the Kustomization should not fail.", err)
+	}
+
+	manifest, found := (*manifests)[ResourceID{Kind: "ConfigMap", Name: "config"}]
+	if len(*manifests) != 1 || !found || manifest.Content != kustomization || manifest.Line != 1 {
+		t.Fatal("A block scalar containing '---' should not split the document.", *manifests)
+	}
+}
+
+func TestSplittingKustomizationReportsLineOfInvalidManifest(t *testing.T) {
+	kustomization := "apiVersion: v1\nkind: Service\nmetadata:\n  name: first\n---\nfoo: bar\n"
+
+	_, err := SplitKustomizationIntoManifests(strings.NewReader(kustomization))
+	if err == nil || !strings.Contains(err.Error(), "starting at line 6") {
+		t.Fatal("Splitting the Kustomization should fail with the starting line of the invalid manifest.", err)
+	}
+}
+
+func TestSplittingKustomizationExpandsListsIntoItems(t *testing.T) {
+	kustomization := "apiVersion: v1\nkind: List\nmetadata:\n  resourceVersion: \"\"\nitems:\n  - apiVersion: v1\n    kind: Service\n    metadata:\n      name: web\n  - apiVersion: apps/v1\n    kind: Deployment\n    metadata:\n      name: web\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: other\n"
+
+	manifests, err := SplitKustomizationIntoManifests(strings.NewReader(kustomization))
+	if err != nil {
+		t.Fatal("Splitting the Kustomization should not fail.", err)
+	}
+
+	if len(*manifests) != 3 {
+		t.Fatal("Splitting the Kustomization should result in the list items and the other manifest.", *manifests)
+	}
+
+	deployment := (*manifests)[ResourceID{Group: "apps", Kind: "Deployment", Name: "web"}]
+	if deployment.Content != "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n" || deployment.Line != 10 || deployment.Index != 1 {
+		t.Fatal("The list item should be parsed as manifest with its starting line.", deployment)
+	}
+
+	other := (*manifests)[ResourceID{Kind: "Service", Name: "other"}]
+	if other.Index != 2 {
+		t.Fatal("The manifest after the list should be positioned after the list items.", other)
+	}
+}
+
+func TestSplittingKustomizationCompletesItemsOfTypedLists(t *testing.T) {
+	kustomization := "apiVersion: v1\nkind: ConfigMapList\nitems:\n  - metadata:\n      name: config\n    data:\n      key: value\n"
+
+	manifests, err := SplitKustomizationIntoManifests(strings.NewReader(kustomization))
+	if err != nil {
+		t.Fatal("Splitting the Kustomization should not fail.", err)
+	}
+
+	manifest, found := (*manifests)[ResourceID{Kind: "ConfigMap", Name: "config"}]
+	if len(*manifests) != 1 || !found || manifest.ApiVersion != "v1" || manifest.Content != "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  key: value\n" {
+		t.Fatal("The items of a typed list should get the kind and apiVersion of the list.", *manifests)
+	}
+}
+
+func TestSplittingKustomizationKeepsNamedResourcesWithListKind(t *testing.T) {
+	kustomization := "apiVersion: example.com/v1\nkind: AccessList\nmetadata:\n  name: admins\nitems:\n  - alice\n"
+
+	manifests, err := SplitKustomizationIntoManifests(strings.NewReader(kustomization))
+	if err != nil {
+		t.Fatal("Splitting the Kustomization should not fail.", err)
+	}
+
+	if _, found := (*manifests)[ResourceID{Group: "example.com", Kind: "AccessList", Name: "admins"}]; len(*manifests) != 1 || !found {
+		t.Fatal("A named resource whose kind ends with 'List' should not be expanded.", *manifests)
+	}
+}
+
+func TestSplittingKustomizationCountsIndexOfDuplicateManifests(t *testing.T) {
+	kustomization := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: other\n"
+
+	manifests, err := SplitKustomizationIntoManifests(strings.NewReader(kustomization))
+	if err != nil {
+		t.Fatal("Splitting the Kustomization should not fail.", err)
+	}
+
+	web, other := (*manifests)[ResourceID{Kind: "Service", Name: "web"}], (*manifests)[ResourceID{Kind: "Service", Name: "other"}]
+	if len(*manifests) != 2 || web.Index != 1 || other.Index != 2 {
+		t.Fatal("The index should be the position of the document, also if a manifest is replaced by a duplicate.", *manifests)
+	}
+}
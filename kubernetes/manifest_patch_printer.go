@@ -0,0 +1,60 @@
+package kubernetes
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Prints the given diffs as multi-file patch which can be applied using 'git apply'.
+// Each resource is represented by a virtual file, see ResourceID.FilePath(), whose content is the raw text of the
+// manifest. The patch is created from the raw texts without any normalization, so that it applies to the rendered
+// manifests, i.e. it contains reordered lists, ignored fields and unredacted secrets. Unchanged resources are skipped.
+// Headers are printed before the patch of each file, where they are ignored by 'git apply'.
+func PrintPatch(diffs []ManifestDiff, contextLines int, printHeaders bool, output io.Writer) {
+	for _, diff := range diffs {
+		if diff.ChangeType == ChangeTypeUnchanged {
+			continue
+		}
+
+		lines := createDiffLines(splitIntoLines(diff.OldManifest.Raw()), splitIntoLines(diff.NewManifest.Raw()))
+		if !slices.ContainsFunc(lines, func(line DiffLine) bool { return line.Operation != DiffOperationEqual }) {
+			continue
+		}
+
+		if printHeaders {
+			PrintDiffHeader(&diff, false, output)
+		}
+
+		// Added and removed resources use the same file path on both sides, just like git does.
+		oldFile, newFile := diff.ID.FilePath(), diff.ID.FilePath()
+		if diff.ChangeType == ChangeTypeModified || diff.ChangeType == ChangeTypeRenamed {
+			oldFile = diff.OldManifest.ID().FilePath()
+		}
+
+		fmt.Fprintf(output, "diff --git a/%s b/%s\n", oldFile, newFile)
+
+		oldPath, newPath := "a/"+oldFile, "b/"+newFile
+		switch {
+		case diff.ChangeType == ChangeTypeAdded:
+			fmt.Fprintln(output, "new file mode 100644")
+			oldPath = "/dev/null"
+		case diff.ChangeType == ChangeTypeRemoved:
+			fmt.Fprintln(output, "deleted file mode 100644")
+			newPath = "/dev/null"
+		case oldFile != newFile:
+			fmt.Fprintln(output, "rename from "+oldFile)
+			fmt.Fprintln(output, "rename to "+newFile)
+		}
+
+		fmt.Fprintln(output, "--- "+oldPath)
+		fmt.Fprintln(output, "+++ "+newPath)
+
+		for _, hunk := range CreateDiffHunks(lines, contextLines) {
+			fmt.Fprintln(output, hunk.Header())
+			for _, line := range hunk.Lines {
+				fmt.Fprintln(output, line.Operation.Prefix()+line.Text)
+			}
+		}
+	}
+}
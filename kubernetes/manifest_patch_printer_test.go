@@ -0,0 +1,148 @@
+package kubernetes
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrintPatchForModifiedManifest(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "v1", Kind: "Service", Name: "backend", Namespace: "my-namespace", Content: "kind: Service\nspec:\n  type: ClusterIP\n"}
+	newManifest := Manifest{ApiVersion: "v1", Kind: "Service", Name: "backend", Namespace: "my-namespace", Content: "kind: Service\nspec:\n  type: NodePort\n"}
+	diff := CreateDiffForManifests(&oldManifest, &newManifest)
+
+	output := new(bytes.Buffer)
+	PrintPatch([]ManifestDiff{*diff}, 3, false, output)
+
+	expectedPatch := `diff --git a/v1/Service/my-namespace/backend.yaml b/v1/Service/my-namespace/backend.yaml
+--- a/v1/Service/my-namespace/backend.yaml
++++ b/v1/Service/my-namespace/backend.yaml
+@@ -1,3 +1,3 @@
+ kind: Service
+ spec:
+-  type: ClusterIP
++  type: NodePort
+`
+
+	if output.String() != expectedPatch {
+		t.Fatal("Patch of a modified manifest should contain file headers and hunks. Patch:\n" + output.String())
+	}
+}
+
+func TestPrintPatchForAddedAndRemovedManifests(t *testing.T) {
+	manifest := Manifest{ApiVersion: "v1", Kind: "Namespace", Name: "my-namespace", Content: "kind: Namespace\n"}
+	addedDiff := CreateDiffForManifests(&Manifest{}, &manifest)
+	removedDiff := CreateDiffForManifests(&manifest, &Manifest{})
+
+	output := new(bytes.Buffer)
+	PrintPatch([]ManifestDiff{*addedDiff, *removedDiff}, 3, false, output)
+
+	expectedPatch := `diff --git a/v1/Namespace/my-namespace.yaml b/v1/Namespace/my-namespace.yaml
+new file mode 100644
+--- /dev/null
++++ b/v1/Namespace/my-namespace.yaml
+@@ -0,0 +1 @@
++kind: Namespace
+diff --git a/v1/Namespace/my-namespace.yaml b/v1/Namespace/my-namespace.yaml
+deleted file mode 100644
+--- a/v1/Namespace/my-namespace.yaml
++++ /dev/null
+@@ -1 +0,0 @@
+-kind: Namespace
+`
+
+	if output.String() != expectedPatch {
+		t.Fatal("Patch of added and removed manifests should use /dev/null. Patch:\n" + output.String())
+	}
+}
+
+func TestPrintPatchForApiVersionBumpContainsRename(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "autoscaling/v2beta2", Kind: "HorizontalPodAutoscaler", Name: "backend", Content: "apiVersion: autoscaling/v2beta2\n"}
+	newManifest := Manifest{ApiVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscaler", Name: "backend", Content: "apiVersion: autoscaling/v2\n"}
+	diff := CreateDiffForManifests(&oldManifest, &newManifest)
+
+	output := new(bytes.Buffer)
+	PrintPatch([]ManifestDiff{*diff}, 3, false, output)
+
+	expectedPatch := `diff --git a/autoscaling_v2beta2/HorizontalPodAutoscaler/backend.yaml b/autoscaling_v2/HorizontalPodAutoscaler/backend.yaml
+rename from autoscaling_v2beta2/HorizontalPodAutoscaler/backend.yaml
+rename to autoscaling_v2/HorizontalPodAutoscaler/backend.yaml
+--- a/autoscaling_v2beta2/HorizontalPodAutoscaler/backend.yaml
++++ b/autoscaling_v2/HorizontalPodAutoscaler/backend.yaml
+@@ -1 +1 @@
+-apiVersion: autoscaling/v2beta2
++apiVersion: autoscaling/v2
+`
+
+	if output.String() != expectedPatch {
+		t.Fatal("Patch of a manifest with changed file path should contain a rename. Patch:\n" + output.String())
+	}
+}
+
+func TestPrintPatchWithHeadersPrintsHeaderBeforeEachFile(t *testing.T) {
+	manifest := Manifest{ApiVersion: "v1", Kind: "Namespace", Name: "my-namespace", Content: "kind: Namespace\n"}
+	diff := CreateDiffForManifests(&Manifest{}, &manifest)
+
+	output := new(bytes.Buffer)
+	PrintPatch([]ManifestDiff{*diff}, 3, true, output)
+
+	if !bytes.HasPrefix(output.Bytes(), []byte("Namespace my-namespace (added, +1 -0)\ndiff --git ")) {
+		t.Fatal("Patch should contain the header before the file patch. Patch:\n" + output.String())
+	}
+}
+
+func TestPrintPatchCanBeAppliedToRawManifests(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Applying the patch requires git.")
+	}
+
+	oldKustomization := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  annotations:\n    checksum/config: abc\nspec:\n  template:\n    spec:\n      containers:\n      - name: app\n        image: app:1\n      - name: sidecar\n        image: sidecar:1\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: token\ndata:\n  token: c2VjcmV0\n"
+	newKustomization := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  annotations:\n    checksum/config: def\nspec:\n  template:\n    spec:\n      containers:\n      - name: sidecar\n        image: sidecar:1\n      - name: app\n        image: app:2\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: token\ndata:\n  token: b3RoZXI=\n"
+
+	rule, _ := ParseIgnoreRule(`metadata.annotations["checksum/config"]`)
+	report, err := CreateDiffForManifestFiles(&oldKustomization, &newKustomization, &DiffOptions{Semantic: true, MergeKeys: DefaultMergeKeys, RedactSecrets: true, IgnoreRules: []IgnoreRule{rule}})
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail.", err)
+	}
+
+	directory := t.TempDir()
+	writeManifestFiles(t, directory, oldKustomization)
+
+	patch := new(bytes.Buffer)
+	PrintPatch(report.Diffs, 3, true, patch)
+
+	command := exec.Command("git", "apply", "-")
+	command.Dir, command.Stdin = directory, patch
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Fatal("Applying the patch should not fail. Output:\n"+string(output), err)
+	}
+
+	manifests, _ := SplitKustomizationIntoManifests(strings.NewReader(newKustomization))
+	for _, manifest := range *manifests {
+		content, err := os.ReadFile(filepath.Join(directory, manifest.ID().FilePath()))
+		if err != nil || string(content) != manifest.RawContent {
+			t.Fatal("The patched file should contain the raw new manifest. Content:\n"+string(content), err)
+		}
+	}
+}
+
+func writeManifestFiles(t *testing.T, directory string, kustomization string) {
+	manifests, err := SplitKustomizationIntoManifests(strings.NewReader(kustomization))
+	if err != nil {
+		t.Fatal("Splitting the Kustomization should not fail.", err)
+	}
+
+	for _, manifest := range *manifests {
+		path := filepath.Join(directory, manifest.ID().FilePath())
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal("Creating the directory should not fail.", err)
+		}
+
+		if err := os.WriteFile(path, []byte(manifest.RawContent), 0o644); err != nil {
+			t.Fatal("Writing the manifest should not fail.", err)
+		}
+	}
+}
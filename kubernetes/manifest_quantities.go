@@ -0,0 +1,216 @@
+package kubernetes
+
+import (
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A pattern for the location of fields, using the syntax of ignore rules. Like merge keys, it is matched against
+// the end of the location. If the kind is set, the pattern only applies to manifests of this kind.
+type fieldPattern struct {
+	Kind     string
+	Segments []pathSegment
+}
+
+// Parses the given field pattern and panics if it is invalid. Only used for the built-in patterns.
+func mustParseFieldPattern(pattern string) fieldPattern {
+	rule, err := ParseIgnoreRule(pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	return fieldPattern(rule)
+}
+
+// Determines whether the pattern matches the given location of the given manifest.
+func (p fieldPattern) matches(manifest *Manifest, location []string) bool {
+	return matchesLocation(p.Kind, p.Segments, manifest, location)
+}
+
+// The mappings whose values are resource quantities, e.g. 'resources.requests' with values like 'cpu: 500m'.
+// The keys of these mappings are resource names, which may contain characters not supported by patterns, e.g. 'nvidia.com/gpu'.
+var quantityMappings = []fieldPattern{
+	mustParseFieldPattern("resources.requests"),
+	mustParseFieldPattern("resources.limits"),
+	mustParseFieldPattern("overhead"),
+	mustParseFieldPattern("RuntimeClass:overhead.podFixed"),
+	mustParseFieldPattern("ResourceQuota:spec.hard"),
+	mustParseFieldPattern("PersistentVolume:spec.capacity"),
+	mustParseFieldPattern("LimitRange:spec.limits[*].max"),
+	mustParseFieldPattern("LimitRange:spec.limits[*].min"),
+	mustParseFieldPattern("LimitRange:spec.limits[*].default"),
+	mustParseFieldPattern("LimitRange:spec.limits[*].defaultRequest"),
+	mustParseFieldPattern("LimitRange:spec.limits[*].maxLimitRequestRatio"),
+}
+
+// The fields whose values are resource quantities.
+var quantityFields = []fieldPattern{
+	mustParseFieldPattern("emptyDir.sizeLimit"),
+}
+
+// The fields whose values are durations like '30s' or '1h30m', e.g. of cert-manager certificates and Flux resources.
+var durationFields = []fieldPattern{
+	mustParseFieldPattern("duration"),
+	mustParseFieldPattern("renewBefore"),
+	mustParseFieldPattern("interval"),
+	mustParseFieldPattern("retryInterval"),
+	mustParseFieldPattern("timeout"),
+}
+
+// A resource quantity like '500m', '1.5Gi' or '1e3', consisting of a number and an optional suffix or exponent.
+var quantityPattern = regexp.MustCompile(`^([+-]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+))(Ki|Mi|Gi|Ti|Pi|Ei|n|u|m|k|M|G|T|P|E|[eE][+-]?[0-9]+)?$`)
+
+// The factors of the binary suffixes of resource quantities as powers of two.
+var binaryQuantitySuffixes = map[string]int64{"Ki": 10, "Mi": 20, "Gi": 30, "Ti": 40, "Pi": 50, "Ei": 60}
+
+// The factors of the decimal suffixes of resource quantities as powers of ten.
+var decimalQuantitySuffixes = map[string]int64{"n": -9, "u": -6, "m": -3, "": 0, "k": 3, "M": 6, "G": 9, "T": 12, "P": 15, "E": 18}
+
+// Normalizes the value of the field at the given location if it is a resource quantity or a duration, e.g. '1Gi'
+// becomes '1073741824', '500m' becomes '0.5' and '0.5m' becomes '30s'. Returns false if the field is not a quantity
+// or duration field, or if the value cannot be parsed.
+func normalizeFieldValue(manifest *Manifest, location []string, value string) (string, bool) {
+	if isQuantityField(manifest, location) {
+		quantity, ok := parseQuantity(value)
+		if !ok {
+			return "", false
+		}
+
+		return formatQuantity(quantity), true
+	}
+
+	if isDurationField(manifest, location) {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return "", false
+		}
+
+		return duration.String(), true
+	}
+
+	return "", false
+}
+
+// Determines whether the given values of the field at the given location are equivalent resource quantities or
+// durations. Quantities are compared as exact numbers, so that e.g. '100m' and '125m' are never considered equal.
+func areEquivalentValues(manifest *Manifest, location []string, old string, new string) bool {
+	if isQuantityField(manifest, location) {
+		oldQuantity, oldOk := parseQuantity(old)
+		newQuantity, newOk := parseQuantity(new)
+
+		return oldOk && newOk && oldQuantity.Cmp(newQuantity) == 0
+	}
+
+	if isDurationField(manifest, location) {
+		oldDuration, oldErr := time.ParseDuration(old)
+		newDuration, newErr := time.ParseDuration(new)
+
+		return oldErr == nil && newErr == nil && oldDuration == newDuration
+	}
+
+	return false
+}
+
+// Determines whether the field at the given location of the given manifest is a resource quantity.
+func isQuantityField(manifest *Manifest, location []string) bool {
+	return slices.ContainsFunc(quantityFields, func(p fieldPattern) bool { return p.matches(manifest, location) }) ||
+		(len(location) > 0 && slices.ContainsFunc(quantityMappings, func(p fieldPattern) bool { return p.matches(manifest, location[:len(location)-1]) }))
+}
+
+// Determines whether the field at the given location of the given manifest is a duration.
+func isDurationField(manifest *Manifest, location []string) bool {
+	return slices.ContainsFunc(durationFields, func(p fieldPattern) bool { return p.matches(manifest, location) })
+}
+
+// Parses the given resource quantity into an exact number.
+func parseQuantity(value string) (*big.Rat, bool) {
+	match := quantityPattern.FindStringSubmatch(value)
+	if match == nil {
+		return nil, false
+	}
+
+	number, ok := new(big.Rat).SetString(match[1])
+	if !ok {
+		return nil, false
+	}
+
+	base, exponent := int64(10), int64(0)
+	if shift, binary := binaryQuantitySuffixes[match[2]]; binary {
+		base, exponent = 2, shift
+	} else if shift, decimal := decimalQuantitySuffixes[match[2]]; decimal {
+		exponent = shift
+	} else {
+		// Large exponents are not valid quantities and are rejected, as they would be expensive to compute.
+		parsed, err := strconv.ParseInt(match[2][1:], 10, 64)
+		if err != nil || parsed < -100 || parsed > 100 {
+			return nil, false
+		}
+
+		exponent = parsed
+	}
+
+	factor := new(big.Int).Exp(big.NewInt(base), big.NewInt(max(exponent, -exponent)), nil)
+	if exponent < 0 {
+		return number.Quo(number, new(big.Rat).SetInt(factor)), true
+	}
+
+	return number.Mul(number, new(big.Rat).SetInt(factor)), true
+}
+
+// Formats the given quantity as a plain decimal number without suffix, e.g. '0.5' or '1073741824'.
+func formatQuantity(quantity *big.Rat) string {
+	if quantity.IsInt() {
+		return quantity.Num().String()
+	}
+
+	// The denominator of a parsed quantity divides a power of ten, e.g. 1/8 for '125m', so the value can be represented
+	// exactly with the exponent of the smallest such power as number of digits.
+	digits, power := 0, big.NewInt(1)
+	for new(big.Int).Mod(power, quantity.Denom()).Sign() != 0 && digits < 1000 {
+		power.Mul(power, big.NewInt(10))
+		digits++
+	}
+
+	result := quantity.FloatString(digits)
+
+	return strings.TrimRight(strings.TrimRight(result, "0"), ".")
+}
+
+// Replaces the values of quantity and duration fields in the old node with the values of the new node if they
+// are equivalent, e.g. '1000m' and '1', so that they do not show up as changes. Returns true if any value was replaced.
+func alignEquivalentValues(manifest *Manifest, mergeKeys []MergeKey, location []string, old *yaml.Node, new *yaml.Node) bool {
+	if old == nil || new == nil || old.Kind != new.Kind {
+		return false
+	}
+
+	aligned := false
+	switch old.Kind {
+	case yaml.MappingNode:
+		for _, key := range mappingKeys(old) {
+			aligned = alignEquivalentValues(manifest, mergeKeys, append(slices.Clip(location), key), mappingValue(old, key), mappingValue(new, key)) || aligned
+		}
+	case yaml.SequenceNode:
+		itemLocation := append(slices.Clip(location), "[]")
+		for _, pair := range matchSequenceItems(manifest, mergeKeys, location, old, new) {
+			aligned = alignEquivalentValues(manifest, mergeKeys, itemLocation, pair.Old, pair.New) || aligned
+		}
+	case yaml.ScalarNode:
+		if old.Value == new.Value && old.ShortTag() == new.ShortTag() {
+			return false
+		}
+
+		if areEquivalentValues(manifest, location, old.Value, new.Value) {
+			old.Value, old.Tag, old.Style = new.Value, new.Tag, new.Style
+
+			return true
+		}
+	}
+
+	return aligned
+}
@@ -0,0 +1,117 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestNormalizeFieldValueNormalizesQuantities(t *testing.T) {
+	manifest := Manifest{ApiVersion: "v1", Kind: "Pod"}
+	location := []string{"spec", "containers", "[]", "resources", "limits", "cpu"}
+
+	tests := map[string]string{
+		"1":      "1",
+		"1000m":  "1",
+		"500m":   "0.5",
+		".5":     "0.5",
+		"1.":     "1",
+		"1Gi":    "1073741824",
+		"1024Mi": "1073741824",
+		"1k":     "1000",
+		"1e3":    "1000",
+		"1E":     "1000000000000000000",
+		"100n":   "0.0000001",
+		"-2.5M":  "-2500000",
+		"125m":   "0.125",
+		"250m":   "0.25",
+		"1.5Ki":  "1536",
+		"0.5Ki":  "512",
+		"3n":     "0.000000003",
+	}
+
+	for value, expected := range tests {
+		result, ok := normalizeFieldValue(&manifest, location, value)
+		if !ok || result != expected {
+			t.Fatal("The quantity '"+value+"' should be normalized to '"+expected+"', got '"+result+"'.", ok)
+		}
+	}
+
+	for _, value := range []string{"", "abc", "1Gb", "1.2.3", "1e1000", "Gi"} {
+		if _, ok := normalizeFieldValue(&manifest, location, value); ok {
+			t.Fatal("The invalid quantity '" + value + "' should not be normalized.")
+		}
+	}
+}
+
+func TestNormalizeFieldValueNormalizesDurations(t *testing.T) {
+	manifest := Manifest{ApiVersion: "cert-manager.io/v1", Kind: "Certificate"}
+
+	result, ok := normalizeFieldValue(&manifest, []string{"spec", "renewBefore"}, "0.5m")
+	if !ok || result != "30s" {
+		t.Fatal("The duration should be normalized.", result, ok)
+	}
+
+	if _, ok := normalizeFieldValue(&manifest, []string{"spec", "renewBefore"}, "30"); ok {
+		t.Fatal("Durations without unit should not be normalized.")
+	}
+}
+
+func TestNormalizeFieldValueOnlyNormalizesKnownFields(t *testing.T) {
+	tests := []struct {
+		manifest Manifest
+		location []string
+		expected bool
+	}{
+		{Manifest{Kind: "Deployment"}, []string{"spec", "template", "spec", "containers", "[]", "resources", "requests", "memory"}, true},
+		{Manifest{Kind: "Deployment"}, []string{"spec", "template", "spec", "containers", "[]", "resources", "limits", "nvidia.com/gpu"}, true},
+		{Manifest{Kind: "Deployment"}, []string{"spec", "template", "spec", "volumes", "[]", "emptyDir", "sizeLimit"}, true},
+		{Manifest{Kind: "PersistentVolumeClaim"}, []string{"spec", "resources", "requests", "storage"}, true},
+		{Manifest{Kind: "ResourceQuota"}, []string{"spec", "hard", "requests.cpu"}, true},
+		{Manifest{Kind: "LimitRange"}, []string{"spec", "limits", "[]", "default", "cpu"}, true},
+		{Manifest{Kind: "Deployment"}, []string{"spec", "hard", "cpu"}, false},
+		{Manifest{Kind: "Deployment"}, []string{"spec", "replicas"}, false},
+		{Manifest{Kind: "Deployment"}, []string{"spec", "template", "spec", "containers", "[]", "resources", "limits"}, false},
+	}
+
+	for _, test := range tests {
+		if _, ok := normalizeFieldValue(&test.manifest, test.location, "1"); ok != test.expected {
+			t.Fatal("Only known quantity fields should be normalized.", test.manifest.Kind, test.location)
+		}
+	}
+}
+
+func TestAlignEquivalentValuesReplacesEquivalentOldValues(t *testing.T) {
+	oldContent := "spec:\n  containers:\n  - name: app\n    resources:\n      requests:\n        cpu: 1000m\n        memory: 1Gi\n      limits:\n        cpu: 500m\n"
+	newContent := "spec:\n  containers:\n  - name: app\n    resources:\n      requests:\n        cpu: \"1\"\n        memory: 1024Mi\n      limits:\n        cpu: 1\n"
+	expectedContent := "spec:\n  containers:\n    - name: app\n      resources:\n        requests:\n          cpu: \"1\"\n          memory: 1024Mi\n        limits:\n          cpu: 500m\n"
+
+	var oldDocument, newDocument yaml.Node
+	_ = yaml.Unmarshal([]byte(oldContent), &oldDocument)
+	_ = yaml.Unmarshal([]byte(newContent), &newDocument)
+
+	if !alignEquivalentValues(&Manifest{Kind: "Pod"}, DefaultMergeKeys, nil, documentRoot(&oldDocument), documentRoot(&newDocument)) {
+		t.Fatal("Aligning should report that the old document was changed.")
+	}
+
+	result, _ := encodeYamlDocument(&oldDocument)
+	if result != expectedContent {
+		t.Fatal("Only equivalent values should be replaced. Result:\n" + result)
+	}
+}
+
+func TestAlignEquivalentValuesKeepsDifferentQuantities(t *testing.T) {
+	tests := [][2]string{{"100m", "125m"}, {"250m", "300m"}, {"1Gi", "1000Mi"}}
+
+	for _, test := range tests {
+		oldContent, newContent := "spec:\n  containers:\n  - name: app\n    resources:\n      limits:\n        cpu: "+test[0]+"\n", "spec:\n  containers:\n  - name: app\n    resources:\n      limits:\n        cpu: "+test[1]+"\n"
+
+		var oldDocument, newDocument yaml.Node
+		_ = yaml.Unmarshal([]byte(oldContent), &oldDocument)
+		_ = yaml.Unmarshal([]byte(newContent), &newDocument)
+
+		if alignEquivalentValues(&Manifest{Kind: "Pod"}, DefaultMergeKeys, nil, documentRoot(&oldDocument), documentRoot(&newDocument)) {
+			t.Fatal("The quantities '" + test[0] + "' and '" + test[1] + "' should not be aligned, as they differ.")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package kubernetes
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The prefix of redacted values. It is followed by the fingerprint of the value.
+const redactedValuePrefix = "redacted:"
+
+// Creates a transformation which replaces the values of Secret.data and Secret.stringData with a fingerprint,
+// so that added, removed and changed keys are still visible without revealing the values.
+// The fingerprint is a truncated HMAC of the value using the given salt. If no salt is given, a random salt is used,
+// so that fingerprints cannot be compared across runs.
+func createSecretRedaction(salt []byte) manifestTransformation {
+	if len(salt) == 0 {
+		salt = make([]byte, 32)
+		_, _ = rand.Read(salt)
+	}
+
+	return func(manifest *Manifest, document *yaml.Node) bool {
+		group, _ := SplitApiVersion(manifest.ApiVersion)
+		if manifest.Kind != "Secret" || group != "" {
+			return false
+		}
+
+		redacted := false
+		for _, root := range document.Content {
+			for _, field := range []string{"data", "stringData"} {
+				values := mappingValue(root, field)
+				if values == nil || values.Kind != yaml.MappingNode {
+					continue
+				}
+
+				for i := 1; i < len(values.Content); i += 2 {
+					value := values.Content[i]
+					if value.Kind != yaml.ScalarNode {
+						continue
+					}
+
+					value.Value, value.Tag, value.Style = redactedValuePrefix+fingerprint(salt, value.Value), "!!str", 0
+					redacted = true
+				}
+			}
+		}
+
+		return redacted
+	}
+}
+
+// Creates a short fingerprint of the given value using the given salt.
+func fingerprint(salt []byte, value string) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))[:12]
+}
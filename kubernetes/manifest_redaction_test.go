@@ -0,0 +1,56 @@
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSecretRedactionReplacesValuesWithFingerprints(t *testing.T) {
+	content := "apiVersion: v1\nkind: Secret\ndata:\n  password: c2VjcmV0\n  other: c2VjcmV0\nstringData:\n  token: abc\n"
+	manifest := Manifest{ApiVersion: "v1", Kind: "Secret"}
+
+	var document yaml.Node
+	_ = yaml.Unmarshal([]byte(content), &document)
+
+	if !createSecretRedaction([]byte("salt"))(&manifest, &document) {
+		t.Fatal("The redaction should report that the document was changed.")
+	}
+
+	result, _ := encodeYamlDocument(&document)
+	if strings.Contains(result, "c2VjcmV0") || strings.Contains(result, "abc") {
+		t.Fatal("The secret values should be redacted. Result:\n" + result)
+	}
+
+	expectedContent := "apiVersion: v1\nkind: Secret\ndata:\n  password: redacted:" + fingerprint([]byte("salt"), "c2VjcmV0") +
+		"\n  other: redacted:" + fingerprint([]byte("salt"), "c2VjcmV0") + "\nstringData:\n  token: redacted:" + fingerprint([]byte("salt"), "abc") + "\n"
+	if result != expectedContent {
+		t.Fatal("The secret values should be replaced by their fingerprints. Result:\n" + result)
+	}
+}
+
+func TestSecretRedactionIgnoresOtherKinds(t *testing.T) {
+	for _, manifest := range []Manifest{{ApiVersion: "v1", Kind: "ConfigMap"}, {ApiVersion: "example.com/v1", Kind: "Secret"}} {
+		var document yaml.Node
+		_ = yaml.Unmarshal([]byte("data:\n  password: c2VjcmV0\n"), &document)
+
+		if createSecretRedaction(nil)(&manifest, &document) {
+			t.Fatal("Only core Secrets should be redacted.", manifest)
+		}
+	}
+}
+
+func TestFingerprintDependsOnSaltAndValue(t *testing.T) {
+	if fingerprint([]byte("a"), "value") != fingerprint([]byte("a"), "value") {
+		t.Fatal("The fingerprint should be deterministic for the same salt and value.")
+	}
+
+	if fingerprint([]byte("a"), "value") == fingerprint([]byte("b"), "value") || fingerprint([]byte("a"), "value") == fingerprint([]byte("a"), "other") {
+		t.Fatal("The fingerprint should depend on salt and value.")
+	}
+
+	if len(fingerprint([]byte("a"), "value")) != 12 {
+		t.Fatal("The fingerprint should be short.")
+	}
+}
@@ -0,0 +1,137 @@
+package kubernetes
+
+import (
+	"cmp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A possible rename of a removed manifest to an added manifest with the similarity of their content.
+type renameCandidate struct {
+	OldKey     ResourceID
+	NewKey     ResourceID
+	Similarity float64
+}
+
+// Detects renamed manifests by pairing removed and added manifests of the same group, kind and namespace whose
+// content has at least the given similarity (between 0 and 1). The most similar pairs are matched first.
+// The old version of a renamed manifest is moved to the key of the new version, so that both versions are diffed
+// with each other. Returns the number of detected renames.
+func DetectRenamedManifests(old *ManifestMap, new *ManifestMap, threshold float64) int {
+	if threshold <= 0 {
+		return 0
+	}
+
+	// The content of each removed and added manifest is only stripped and split once, as it is compared to many candidates.
+	removedLines, addedLines := collectUnmatchedManifestLines(old, new), collectUnmatchedManifestLines(new, old)
+
+	var candidates []renameCandidate
+	for oldKey, oldLines := range removedLines {
+		for newKey, newLines := range addedLines {
+			if !isSameKindAndNamespace(oldKey, newKey) {
+				continue
+			}
+
+			if similarity := calculateLineSimilarity(oldLines, newLines); similarity >= threshold {
+				candidates = append(candidates, renameCandidate{OldKey: oldKey, NewKey: newKey, Similarity: similarity})
+			}
+		}
+	}
+
+	// The order of equally similar candidates is determined by their identities, as the iteration order of maps is random.
+	slices.SortFunc(candidates, func(a, b renameCandidate) int {
+		return cmp.Or(cmp.Compare(b.Similarity, a.Similarity), compareResourceIDs(a.OldKey, b.OldKey), compareResourceIDs(a.NewKey, b.NewKey))
+	})
+
+	renamed := make(map[ResourceID]bool)
+	for _, candidate := range candidates {
+		if renamed[candidate.OldKey] || renamed[candidate.NewKey] {
+			continue
+		}
+
+		(*old)[candidate.NewKey] = (*old)[candidate.OldKey]
+		delete(*old, candidate.OldKey)
+		renamed[candidate.OldKey], renamed[candidate.NewKey] = true, true
+	}
+
+	return len(renamed) / 2
+}
+
+// Collects the lines of the content without identity of all manifests which have no counterpart with the same key.
+func collectUnmatchedManifestLines(manifests *ManifestMap, counterparts *ManifestMap) map[ResourceID][]string {
+	lines := make(map[ResourceID][]string)
+	for key, manifest := range *manifests {
+		if _, exists := (*counterparts)[key]; !exists {
+			lines[key] = splitIntoLinesWithoutIdentity(&manifest)
+		}
+	}
+
+	return lines
+}
+
+// Determines whether the given keys belong to resources of the same group, kind and namespace.
+func isSameKindAndNamespace(a ResourceID, b ResourceID) bool {
+	return a.Group == b.Group && a.Kind == b.Kind && a.Namespace == b.Namespace
+}
+
+// Splits the content of the given manifest into lines, without apiVersion, kind, name and namespace, as they are the
+// same for all candidates or differ by definition.
+func splitIntoLinesWithoutIdentity(manifest *Manifest) []string {
+	return splitIntoLines(stripManifestIdentity(manifest.Content))
+}
+
+// Calculates the similarity of the given lines of two manifests as the share of unchanged lines, between 0 and 1.
+// Manifests without lines have no similarity, so that e.g. a removed and an added Namespace are not paired.
+func calculateLineSimilarity(oldLines []string, newLines []string) float64 {
+	if len(oldLines) == 0 || len(newLines) == 0 {
+		return 0
+	}
+
+	unchanged := 0
+	for _, line := range createDiffLines(oldLines, newLines) {
+		if line.Operation == DiffOperationEqual {
+			unchanged++
+		}
+	}
+
+	return float64(2*unchanged) / float64(len(oldLines)+len(newLines))
+}
+
+// Removes the apiVersion, kind, name and namespace from the given manifest content. Returns an empty string if
+// the manifest has no further content.
+// The content is returned unchanged if it cannot be parsed.
+func stripManifestIdentity(content string) string {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return content
+	}
+
+	root := documentRoot(&document)
+	if root == nil || root.Kind != yaml.MappingNode {
+		return content
+	}
+
+	removeMappingKey(root, "apiVersion")
+	removeMappingKey(root, "kind")
+
+	if metadata := mappingValue(root, "metadata"); metadata != nil && metadata.Kind == yaml.MappingNode {
+		removeMappingKey(metadata, "name")
+		removeMappingKey(metadata, "namespace")
+
+		if len(metadata.Content) == 0 {
+			removeMappingKey(root, "metadata")
+		}
+	}
+
+	if len(root.Content) == 0 {
+		return ""
+	}
+
+	result, err := encodeYamlDocument(&document)
+	if err != nil {
+		return content
+	}
+
+	return result
+}
@@ -0,0 +1,71 @@
+package kubernetes
+
+import "testing"
+
+func TestDetectRenamedManifestsPairsSimilarManifests(t *testing.T) {
+	oldConfig := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "app-config-7h2k9", Namespace: "apps", Content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config-7h2k9\n  namespace: apps\ndata:\n  a: \"1\"\n  b: \"2\"\n  c: \"3\"\n  d: \"4\"\n"}
+	newConfig := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "app-config-m4t8c", Namespace: "apps", Content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config-m4t8c\n  namespace: apps\ndata:\n  a: \"1\"\n  b: \"2\"\n  c: \"3\"\n  d: \"5\"\n"}
+	otherNamespace := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "app-config-x9z8w", Namespace: "other", Content: newConfig.Content}
+	unrelated := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "unrelated", Namespace: "apps", Content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: unrelated\n  namespace: apps\ndata:\n  x: \"9\"\n"}
+
+	oldManifests := ManifestMap{oldConfig.Key(): oldConfig}
+	newManifests := ManifestMap{newConfig.Key(): newConfig, otherNamespace.Key(): otherNamespace, unrelated.Key(): unrelated}
+
+	if DetectRenamedManifests(&oldManifests, &newManifests, 0.8) != 1 {
+		t.Fatal("Exactly one rename should be detected.", oldManifests)
+	}
+
+	if len(oldManifests) != 1 || oldManifests[newConfig.Key()].Name != "app-config-7h2k9" {
+		t.Fatal("The old manifest should be moved to the key of the new manifest.", oldManifests)
+	}
+}
+
+func TestDetectRenamedManifestsRespectsThreshold(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "a", Content: "data:\n  a: \"1\"\n  b: \"2\"\n"}
+	newManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "b", Content: "data:\n  a: \"1\"\n  b: \"3\"\n"}
+
+	for threshold, expected := range map[float64]int{0: 0, 0.5: 1, 0.8: 0} {
+		oldManifests := ManifestMap{oldManifest.Key(): oldManifest}
+		newManifests := ManifestMap{newManifest.Key(): newManifest}
+
+		if DetectRenamedManifests(&oldManifests, &newManifests, threshold) != expected {
+			t.Fatal("Renames should only be detected if the similarity reaches the threshold.", threshold)
+		}
+	}
+}
+
+func TestDetectRenamedManifestsPrefersMostSimilarManifest(t *testing.T) {
+	oldManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "config-a", Content: "data:\n  a: \"1\"\n  b: \"2\"\n  c: \"3\"\n"}
+	similarManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "config-b", Content: "data:\n  a: \"1\"\n  b: \"2\"\n  c: \"4\"\n"}
+	identicalManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "config-c", Content: oldManifest.Content}
+
+	oldManifests := ManifestMap{oldManifest.Key(): oldManifest}
+	newManifests := ManifestMap{similarManifest.Key(): similarManifest, identicalManifest.Key(): identicalManifest}
+
+	DetectRenamedManifests(&oldManifests, &newManifests, 0.5)
+
+	if _, exists := oldManifests[identicalManifest.Key()]; !exists {
+		t.Fatal("The removed manifest should be paired with the most similar added manifest.", oldManifests)
+	}
+}
+
+func TestCalculateLineSimilarityIgnoresIdentity(t *testing.T) {
+	old := Manifest{Content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n  namespace: apps\ndata:\n  key: value\n"}
+	new := Manifest{Content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n  namespace: apps\ndata:\n  key: value\n"}
+
+	if similarity := calculateLineSimilarity(splitIntoLinesWithoutIdentity(&old), splitIntoLinesWithoutIdentity(&new)); similarity != 1 {
+		t.Fatal("Manifests which only differ in their name should be identical.", similarity)
+	}
+}
+
+func TestDetectRenamedManifestsIgnoresManifestsWithoutContent(t *testing.T) {
+	oldNamespace := Manifest{ApiVersion: "v1", Kind: "Namespace", Name: "team-a", Content: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: team-a\n"}
+	newNamespace := Manifest{ApiVersion: "v1", Kind: "Namespace", Name: "team-b", Content: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: team-b\n"}
+
+	oldManifests := ManifestMap{oldNamespace.Key(): oldNamespace}
+	newManifests := ManifestMap{newNamespace.Key(): newNamespace}
+
+	if DetectRenamedManifests(&oldManifests, &newManifests, 0.8) != 0 || oldManifests[oldNamespace.Key()] != oldNamespace {
+		t.Fatal("Manifests which only consist of their identity should not be paired.", oldManifests)
+	}
+}
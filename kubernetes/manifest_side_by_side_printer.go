@@ -0,0 +1,145 @@
+package kubernetes
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Options which control how a side-by-side diff is printed.
+// A negative number of context lines prints all lines instead of hunks with the given context.
+type SideBySideOptions struct {
+	Width        int
+	ContextLines int
+	Color        bool
+}
+
+// A pair of aligned lines of a side-by-side diff. Removed and added lines of the same change block are paired,
+// surplus lines of either side are paired with a missing line on the other side.
+// Line numbers are one-based; a missing line has the line number zero.
+type DiffLinePair struct {
+	Changed   bool
+	OldNumber int
+	OldText   string
+	NewNumber int
+	NewText   string
+}
+
+// Returns true if the pair has a line on the old side.
+func (p DiffLinePair) HasOld() bool {
+	return p.OldNumber > 0
+}
+
+// Returns true if the pair has a line on the new side.
+func (p DiffLinePair) HasNew() bool {
+	return p.NewNumber > 0
+}
+
+// Aligns the given diff lines as pairs of old and new lines, starting with the given line numbers.
+func PairDiffLines(lines []DiffLine, oldStart int, newStart int) []DiffLinePair {
+	var pairs []DiffLinePair
+	oldNumber, newNumber := max(oldStart, 1), max(newStart, 1)
+
+	for i := 0; i < len(lines); {
+		if lines[i].Operation == DiffOperationEqual {
+			pairs = append(pairs, DiffLinePair{
+				OldNumber: oldNumber,
+				OldText:   lines[i].Text,
+				NewNumber: newNumber,
+				NewText:   lines[i].Text,
+			})
+			oldNumber, newNumber, i = oldNumber+1, newNumber+1, i+1
+
+			continue
+		}
+
+		// Collect the change block, which consists of removed lines followed by added lines.
+		var removed, added []string
+		for ; i < len(lines) && lines[i].Operation != DiffOperationEqual; i++ {
+			if lines[i].Operation == DiffOperationRemoved {
+				removed = append(removed, lines[i].Text)
+			} else {
+				added = append(added, lines[i].Text)
+			}
+		}
+
+		for j := 0; j < max(len(removed), len(added)); j++ {
+			pair := DiffLinePair{Changed: true}
+			if j < len(removed) {
+				pair.OldNumber, pair.OldText = oldNumber, removed[j]
+				oldNumber++
+			}
+
+			if j < len(added) {
+				pair.NewNumber, pair.NewText = newNumber, added[j]
+				newNumber++
+			}
+
+			pairs = append(pairs, pair)
+		}
+	}
+
+	return pairs
+}
+
+// Prints the diff for two manifests as side-by-side view with the old manifest on the left and the new one on the right.
+// Between both sides, a marker indicates whether a line was changed ('|'), removed ('<') or added ('>').
+func PrintSideBySideDiff(diff *ManifestDiff, options *SideBySideOptions, output io.Writer) {
+	columnWidth := max((options.Width-3)/2, 1)
+
+	hunks := []DiffHunk{{OldStart: 1, NewStart: 1, Lines: diff.Lines}}
+	if options.ContextLines >= 0 {
+		hunks = CreateDiffHunks(diff.Lines, options.ContextLines)
+	}
+
+	for _, hunk := range hunks {
+		if options.ContextLines >= 0 {
+			fmt.Fprintln(output, colorize(hunk.Header(), colorCyan, options.Color))
+		}
+
+		for _, pair := range PairDiffLines(hunk.Lines, hunk.OldStart, hunk.NewStart) {
+			left := padOrTruncate(pair.OldText, columnWidth)
+			right := truncate(pair.NewText, columnWidth)
+
+			marker := " "
+			switch {
+			case !pair.Changed:
+			case !pair.HasNew():
+				marker, left = "<", colorize(left, colorRed, options.Color)
+			case !pair.HasOld():
+				marker, right = ">", colorize(right, colorGreen, options.Color)
+			default:
+				marker = "|"
+				left, right = colorize(left, colorRed, options.Color), colorize(right, colorGreen, options.Color)
+			}
+
+			fmt.Fprintln(output, strings.TrimRight(left+" "+marker+" "+right, " "))
+		}
+	}
+}
+
+// Wraps the given text in the given ANSI color if colors are enabled.
+func colorize(text string, color string, enabled bool) string {
+	if !enabled {
+		return text
+	}
+
+	return color + text + colorReset
+}
+
+// Pads the given text with spaces or truncates it to exactly the given number of characters.
+func padOrTruncate(text string, width int) string {
+	text = truncate(text, width)
+
+	return text + strings.Repeat(" ", width-utf8.RuneCountInString(text))
+}
+
+// Truncates the given text to at most the given number of characters.
+func truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+
+	return string([]rune(text)[:width])
+}
@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPairDiffLinesAlignsChangedLines(t *testing.T) {
+	diff := CreateDiffForManifests(&Manifest{Content: "a\nb\nc\nd"}, &Manifest{Content: "a\nB\nC\nC2\nd"})
+
+	pairs := PairDiffLines(diff.Lines, 1, 1)
+
+	expectedPairs := []DiffLinePair{
+		{Changed: false, OldNumber: 1, OldText: "a", NewNumber: 1, NewText: "a"},
+		{Changed: true, OldNumber: 2, OldText: "b", NewNumber: 2, NewText: "B"},
+		{Changed: true, OldNumber: 3, OldText: "c", NewNumber: 3, NewText: "C"},
+		{Changed: true, OldNumber: 0, OldText: "", NewNumber: 4, NewText: "C2"},
+		{Changed: false, OldNumber: 4, OldText: "d", NewNumber: 5, NewText: "d"},
+	}
+
+	if len(pairs) != len(expectedPairs) {
+		t.Fatal("Changed lines should be paired.", pairs)
+	}
+
+	for i := range pairs {
+		if pairs[i] != expectedPairs[i] {
+			t.Fatal("Changed lines should be paired.", pairs)
+		}
+	}
+}
+
+func TestPrintSideBySideDiffPrintsAllLines(t *testing.T) {
+	diff := CreateDiffForManifests(&Manifest{Content: "spec:\n  replicas: 1\n  paused: true"}, &Manifest{Content: "spec:\n  replicas: 2\n  image: my-app:1.1"})
+
+	output := new(bytes.Buffer)
+	PrintSideBySideDiff(diff, &SideBySideOptions{Width: 35, ContextLines: -1}, output)
+
+	expectedOutput := "spec:" + "              " + "spec:\n" +
+		"  replicas: 1" + "    |   replicas: 2\n" +
+		"  paused: true" + "   |   image: my-app:\n"
+
+	if output.String() != expectedOutput {
+		t.Fatal("Side-by-side diff should align old and new lines and truncate long lines. Diff:\n" + output.String())
+	}
+}
+
+func TestPrintSideBySideDiffPrintsHunksWithMarkers(t *testing.T) {
+	diff := CreateDiffForManifests(&Manifest{Content: "a\nb\nc\nd\ne\nf"}, &Manifest{Content: "a\nb\nd\ne\nf\ng"})
+
+	output := new(bytes.Buffer)
+	PrintSideBySideDiff(diff, &SideBySideOptions{Width: 11, ContextLines: 0}, output)
+
+	expectedOutput := `@@ -3 +2,0 @@
+c    <
+@@ -6,0 +6 @@
+     > g
+`
+
+	if output.String() != expectedOutput {
+		t.Fatal("Side-by-side diff should print hunks with removed and added markers. Diff:\n" + output.String())
+	}
+}
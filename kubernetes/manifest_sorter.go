@@ -0,0 +1,116 @@
+package kubernetes
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+)
+
+// The order in which manifest diffs are returned.
+type SortOrder string
+
+const (
+	SortOrderKind     SortOrder = "kind"
+	SortOrderDocument SortOrder = "document"
+	SortOrderApply    SortOrder = "apply"
+)
+
+// The kinds which are applied first, in the order they are applied by Kubernetes tooling.
+var applyOrderFirst = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"ResourceQuota",
+	"StorageClass",
+	"ServiceAccount",
+	"PodSecurityPolicy",
+	"Role",
+	"ClusterRole",
+	"RoleBinding",
+	"ClusterRoleBinding",
+	"ConfigMap",
+	"Secret",
+	"Endpoints",
+	"Service",
+	"LimitRange",
+	"PriorityClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"Deployment",
+	"StatefulSet",
+	"CronJob",
+	"PodDisruptionBudget",
+}
+
+// The kinds which are applied last, in the order they are applied by Kubernetes tooling.
+var applyOrderLast = []string{
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+// Parses the given string as sort order. An empty string yields the default order by kind.
+func ParseSortOrder(value string) (SortOrder, error) {
+	switch SortOrder(value) {
+	case "", SortOrderKind:
+		return SortOrderKind, nil
+	case SortOrderDocument, SortOrderApply:
+		return SortOrder(value), nil
+	default:
+		return "", errors.New("The sort order '" + value + "' is invalid: must be one of 'kind', 'document' or 'apply'.")
+	}
+}
+
+// Sorts the given diffs in place according to the given sort order.
+// Diffs which are equal according to the sort order are sorted by kind, namespace and name to keep the result stable.
+func SortManifestDiffs(diffs []ManifestDiff, order SortOrder) {
+	slices.SortStableFunc(diffs, func(a, b ManifestDiff) int {
+		switch order {
+		case SortOrderDocument:
+			aRemoved, aIndex := documentPosition(&a)
+			bRemoved, bIndex := documentPosition(&b)
+			if result := cmp.Or(cmp.Compare(aRemoved, bRemoved), cmp.Compare(aIndex, bIndex)); result != 0 {
+				return result
+			}
+		case SortOrderApply:
+			if result := cmp.Compare(applyRank(a.ID.Kind), applyRank(b.ID.Kind)); result != 0 {
+				return result
+			}
+		}
+
+		return compareResourceIDs(a.ID, b.ID)
+	})
+}
+
+// Compares two resource identities by kind, namespace, name, group and version.
+func compareResourceIDs(a ResourceID, b ResourceID) int {
+	return cmp.Or(
+		cmp.Compare(a.Kind, b.Kind),
+		cmp.Compare(a.Namespace, b.Namespace),
+		cmp.Compare(a.Name, b.Name),
+		cmp.Compare(a.Group, b.Group),
+		cmp.Compare(a.Version, b.Version),
+	)
+}
+
+// Returns the position of the diff in the new Kustomization. Removed manifests are placed after all others
+// (signaled by the first return value), in the order of the old Kustomization.
+func documentPosition(diff *ManifestDiff) (int, int) {
+	if diff.ChangeType == ChangeTypeRemoved {
+		return 1, diff.OldManifest.Index
+	}
+
+	return 0, diff.NewManifest.Index
+}
+
+// Returns the rank of the given kind in the apply order. Kinds without explicit order are ranked in between
+// the kinds which are applied first and last.
+func applyRank(kind string) int {
+	if index := slices.Index(applyOrderFirst, kind); index >= 0 {
+		return index
+	}
+
+	if index := slices.Index(applyOrderLast, kind); index >= 0 {
+		return len(applyOrderFirst) + 1 + index
+	}
+
+	return len(applyOrderFirst)
+}
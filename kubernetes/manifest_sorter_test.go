@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseSortOrderAcceptsKnownOrders(t *testing.T) {
+	for _, value := range []string{"", "kind", "document", "apply"} {
+		if _, err := ParseSortOrder(value); err != nil {
+			t.Fatal("The sort order '"+value+"' should be parsed successfully.", err)
+		}
+	}
+}
+
+func TestParseSortOrderRejectsUnknownOrders(t *testing.T) {
+	if _, err := ParseSortOrder("random"); err == nil {
+		t.Fatal("The sort order 'random' should not be parsed successfully.")
+	}
+}
+
+func TestSortManifestDiffsByKind(t *testing.T) {
+	diffs := createDiffsForSorting()
+
+	SortManifestDiffs(diffs, SortOrderKind)
+
+	expectedNames := []string{"my-config", "my-crd", "my-app", "my-namespace", "my-app", "my-app"}
+	if names := collectDiffNames(diffs); !slices.Equal(names, expectedNames) {
+		t.Fatal("Diffs should be sorted by kind, namespace and name.", names)
+	}
+
+	if diffs[4].ID.Namespace != "a-namespace" || diffs[5].ID.Namespace != "b-namespace" {
+		t.Fatal("Diffs with identical kind should be sorted by namespace.")
+	}
+}
+
+func TestSortManifestDiffsByDocument(t *testing.T) {
+	diffs := createDiffsForSorting()
+
+	SortManifestDiffs(diffs, SortOrderDocument)
+
+	expectedIndexes := []int{0, 1, 2, 3, 4}
+	for i, index := range expectedIndexes {
+		if diffs[i].NewManifest.Index != index {
+			t.Fatal("Diffs should be sorted by their position in the new Kustomization.", collectDiffNames(diffs))
+		}
+	}
+
+	if diffs[5].ChangeType != ChangeTypeRemoved {
+		t.Fatal("Removed manifests should be sorted last.")
+	}
+}
+
+func TestSortManifestDiffsByApplyOrder(t *testing.T) {
+	diffs := createDiffsForSorting()
+
+	SortManifestDiffs(diffs, SortOrderApply)
+
+	expectedKinds := []string{"Namespace", "CustomResourceDefinition", "ConfigMap", "Service", "Service", "Deployment"}
+	if kinds := collectDiffKinds(diffs); !slices.Equal(kinds, expectedKinds) {
+		t.Fatal("Diffs should be sorted by apply order.", kinds)
+	}
+}
+
+func TestSortManifestDiffsByApplyOrderPutsCustomResourceDefinitionsAfterNamespaces(t *testing.T) {
+	var diffs []ManifestDiff
+	for _, kind := range []string{"StorageClass", "ResourceQuota", "CustomResourceDefinition", "Namespace"} {
+		manifest := Manifest{ApiVersion: "v1", Kind: kind, Name: "test", Content: "a"}
+		diffs = append(diffs, *CreateDiffForManifests(&Manifest{}, &manifest))
+	}
+
+	SortManifestDiffs(diffs, SortOrderApply)
+
+	expectedKinds := []string{"Namespace", "CustomResourceDefinition", "ResourceQuota", "StorageClass"}
+	if kinds := collectDiffKinds(diffs); !slices.Equal(kinds, expectedKinds) {
+		t.Fatal("Namespaces and CustomResourceDefinitions should be sorted first.", kinds)
+	}
+}
+
+func createDiffsForSorting() []ManifestDiff {
+	manifests := []Manifest{
+		{ApiVersion: "v1", Kind: "Service", Name: "my-app", Namespace: "b-namespace", Content: "b", Index: 0},
+		{ApiVersion: "apps/v1", Kind: "Deployment", Name: "my-app", Namespace: "b-namespace", Content: "b", Index: 1},
+		{ApiVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "my-crd", Content: "b", Index: 2},
+		{ApiVersion: "v1", Kind: "Namespace", Name: "my-namespace", Content: "b", Index: 3},
+		{ApiVersion: "v1", Kind: "Service", Name: "my-app", Namespace: "a-namespace", Content: "b", Index: 4},
+	}
+
+	var diffs []ManifestDiff
+	for _, manifest := range manifests {
+		oldManifest := manifest
+		oldManifest.Content = "a"
+		diffs = append(diffs, *CreateDiffForManifests(&oldManifest, &manifest))
+	}
+
+	removedManifest := Manifest{ApiVersion: "v1", Kind: "ConfigMap", Name: "my-config", Namespace: "a-namespace", Content: "a", Index: 0}
+	diffs = append(diffs, *CreateDiffForManifests(&removedManifest, &Manifest{}))
+
+	return diffs
+}
+
+func collectDiffNames(diffs []ManifestDiff) []string {
+	var names []string
+	for _, diff := range diffs {
+		names = append(names, diff.ID.Name)
+	}
+
+	return names
+}
+
+func collectDiffKinds(diffs []ManifestDiff) []string {
+	var kinds []string
+	for _, diff := range diffs {
+		kinds = append(kinds, diff.ID.Kind)
+	}
+
+	return kinds
+}
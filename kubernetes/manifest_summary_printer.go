@@ -0,0 +1,119 @@
+package kubernetes
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Returns the single-letter status of the change type as used by 'git diff --name-status', e.g. 'M' for modified.
+func (c ChangeType) Status() string {
+	switch c {
+	case ChangeTypeAdded:
+		return "A"
+	case ChangeTypeRemoved:
+		return "D"
+	case ChangeTypeModified:
+		return "M"
+	case ChangeTypeRenamed:
+		return "R"
+	case ChangeTypeReordered:
+		return "O"
+	default:
+		return " "
+	}
+}
+
+// Returns a readable sentence for the summary, e.g. '3 resources changed (1 added, 0 removed, 2 modified, 0 renamed), +10 -4'.
+// Reordered resources are only mentioned if there are any. If changed resources were removed by filters or resources
+// only differ in default values, their numbers are appended, e.g. '(2 filtered out, 1 defaults only)'.
+func (s DiffSummary) String() string {
+	reordered := ""
+	if s.Reordered > 0 {
+		reordered = fmt.Sprintf(", %d reordered", s.Reordered)
+	}
+
+	result := fmt.Sprintf("%d resources changed (%d added, %d removed, %d modified, %d renamed%s), +%d -%d",
+		s.Resources, s.Added, s.Removed, s.Modified, s.Renamed, reordered, s.LinesAdded, s.LinesRemoved)
+
+	var notes []string
+	if s.FilteredOut > 0 {
+		notes = append(notes, fmt.Sprintf("%d filtered out", s.FilteredOut))
+	}
+
+	if s.DefaultsOnly > 0 {
+		notes = append(notes, fmt.Sprintf("%d defaults only", s.DefaultsOnly))
+	}
+
+	if len(notes) > 0 {
+		result += " (" + strings.Join(notes, ", ") + ")"
+	}
+
+	return result
+}
+
+// Prints a table of the changed resources with kind, namespace, name, change type and line counts, followed by the totals.
+// The table is either aligned for terminals or formatted as markdown table. Unchanged resources are skipped.
+func PrintSummaryTable(report *DiffReport, formatAsMarkdownTable bool, output io.Writer) {
+	diffs, summary := report.Diffs, report.Summary()
+
+	if formatAsMarkdownTable {
+		fmt.Fprintln(output, "| Kind | Namespace | Name | Change | + | - |")
+		fmt.Fprintln(output, "| --- | --- | --- | --- | --: | --: |")
+
+		for _, diff := range diffs {
+			if diff.ChangeType != ChangeTypeUnchanged {
+				fmt.Fprintf(output, "| %s | %s | %s | %s | %d | %d |\n", diff.ID.Kind, diff.ID.Namespace, diff.ID.Name, diff.ChangeType, diff.LinesAdded, diff.LinesRemoved)
+			}
+		}
+
+		fmt.Fprintln(output)
+		fmt.Fprintln(output, "**Total:** "+summary.String())
+
+		return
+	}
+
+	writer := tabwriter.NewWriter(output, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "KIND\tNAMESPACE\tNAME\tCHANGE\t+\t-")
+
+	for _, diff := range diffs {
+		if diff.ChangeType != ChangeTypeUnchanged {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%d\n", diff.ID.Kind, diff.ID.Namespace, diff.ID.Name, diff.ChangeType, diff.LinesAdded, diff.LinesRemoved)
+		}
+	}
+
+	writer.Flush()
+
+	fmt.Fprintln(output)
+	fmt.Fprintln(output, summary.String())
+}
+
+// Prints the kind and namespaced name of each changed resource, similar to 'git diff --name-only'.
+func PrintNameOnly(diffs []ManifestDiff, output io.Writer) {
+	for _, diff := range diffs {
+		if diff.ChangeType != ChangeTypeUnchanged {
+			fmt.Fprintln(output, diff.ID.Kind+" "+diff.ID.NamespacedName())
+		}
+	}
+}
+
+// Prints the status letter, kind and namespaced name of each changed resource, similar to 'git diff --name-status'.
+// Like in git, renamed resources are printed with their similarity in percent as well as the old and the new name,
+// e.g. 'R086	ConfigMap my-namespace/old	ConfigMap my-namespace/new'.
+func PrintNameStatus(diffs []ManifestDiff, output io.Writer) {
+	for _, diff := range diffs {
+		switch diff.ChangeType {
+		case ChangeTypeUnchanged:
+			continue
+		case ChangeTypeRenamed:
+			oldID := diff.OldManifest.ID()
+			similarity := calculateLineSimilarity(splitIntoLinesWithoutIdentity(diff.OldManifest), splitIntoLinesWithoutIdentity(diff.NewManifest))
+
+			fmt.Fprintf(output, "%s%03d\t%s %s\t%s %s\n", diff.ChangeType.Status(), int(similarity*100),
+				oldID.Kind, oldID.NamespacedName(), diff.ID.Kind, diff.ID.NamespacedName())
+		default:
+			fmt.Fprintln(output, diff.ChangeType.Status()+"\t"+diff.ID.Kind+" "+diff.ID.NamespacedName())
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package kubernetes
+
+import (
+	"bytes"
+	"testing"
+)
+
+func createDiffsForSummary() []ManifestDiff {
+	oldManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "backend", Namespace: "my-namespace", Content: "a\nb"}
+	newManifest := Manifest{ApiVersion: "apps/v1", Kind: "Deployment", Name: "backend", Namespace: "my-namespace", Content: "a\nc"}
+	namespace := Manifest{ApiVersion: "v1", Kind: "Namespace", Name: "my-namespace", Content: "kind: Namespace"}
+
+	return []ManifestDiff{
+		*CreateDiffForManifests(&oldManifest, &newManifest),
+		*CreateDiffForManifests(&Manifest{}, &namespace),
+		*CreateDiffForManifests(&namespace, &namespace),
+	}
+}
+
+func TestPrintSummaryTablePrintsAlignedTableWithTotals(t *testing.T) {
+	output := new(bytes.Buffer)
+	PrintSummaryTable(&DiffReport{Diffs: createDiffsForSummary()}, false, output)
+
+	expectedOutput := `KIND        NAMESPACE     NAME          CHANGE    +  -
+Deployment  my-namespace  backend       modified  1  1
+Namespace                 my-namespace  added     1  0
+
+2 resources changed (1 added, 0 removed, 1 modified, 0 renamed), +2 -1
+`
+
+	if output.String() != expectedOutput {
+		t.Fatal("The summary table should contain all changed resources and the totals. Output:\n" + output.String())
+	}
+}
+
+func TestPrintSummaryTablePrintsMarkdownTableWithTotals(t *testing.T) {
+	output := new(bytes.Buffer)
+	PrintSummaryTable(&DiffReport{Diffs: createDiffsForSummary()}, true, output)
+
+	expectedOutput := `| Kind | Namespace | Name | Change | + | - |
+| --- | --- | --- | --- | --: | --: |
+| Deployment | my-namespace | backend | modified | 1 | 1 |
+| Namespace |  | my-namespace | added | 1 | 0 |
+
+**Total:** 2 resources changed (1 added, 0 removed, 1 modified, 0 renamed), +2 -1
+`
+
+	if output.String() != expectedOutput {
+		t.Fatal("The markdown summary table should contain all changed resources and the totals. Output:\n" + output.String())
+	}
+}
+
+func TestPrintNameOnlyPrintsChangedResources(t *testing.T) {
+	output := new(bytes.Buffer)
+	PrintNameOnly(createDiffsForSummary(), output)
+
+	if output.String() != "Deployment my-namespace/backend\nNamespace my-namespace\n" {
+		t.Fatal("Only the names of changed resources should be printed. Output:\n" + output.String())
+	}
+}
+
+func TestPrintNameStatusPrintsChangedResourcesWithStatus(t *testing.T) {
+	output := new(bytes.Buffer)
+	PrintNameStatus(createDiffsForSummary(), output)
+
+	if output.String() != "M\tDeployment my-namespace/backend\nA\tNamespace my-namespace\n" {
+		t.Fatal("The status and names of changed resources should be printed. Output:\n" + output.String())
+	}
+}
+
+func TestPrintNameStatusPrintsOldAndNewNameOfRenamedResources(t *testing.T) {
+	oldManifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config-old\n  namespace: apps\ndata:\n  a: \"1\"\n  b: \"2\"\n  c: \"3\"\n"
+	newManifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config-new\n  namespace: apps\ndata:\n  a: \"1\"\n  b: \"2\"\n  c: \"4\"\n"
+
+	report, err := CreateDiffForManifestFiles(&oldManifest, &newManifest, &DiffOptions{RenameThreshold: 0.5})
+	if err != nil {
+		t.Fatal("Diffing manifests should not fail", err)
+	}
+
+	output := new(bytes.Buffer)
+	PrintNameStatus(report.Diffs, output)
+
+	if output.String() != "R075\tConfigMap apps/config-old\tConfigMap apps/config-new\n" {
+		t.Fatal("Renamed resources should be printed with their similarity and both names. Output:\n" + output.String())
+	}
+}
+
+func TestDiffSummaryStringContainsFilteredOutResources(t *testing.T) {
+	summary := DiffSummary{Resources: 1, Modified: 1, LinesAdded: 2, LinesRemoved: 1, FilteredOut: 3}
+
+	expected := "1 resources changed (0 added, 0 removed, 1 modified, 0 renamed), +2 -1 (3 filtered out)"
+	if summary.String() != expected {
+		t.Fatal("The summary should contain the number of filtered out resources. Got: " + summary.String())
+	}
+}
+
+func TestDiffSummaryStringContainsDefaultsOnlyResources(t *testing.T) {
+	summary := DiffSummary{Resources: 1, Modified: 1, LinesAdded: 2, LinesRemoved: 1, FilteredOut: 3, DefaultsOnly: 2}
+
+	expected := "1 resources changed (0 added, 0 removed, 1 modified, 0 renamed), +2 -1 (3 filtered out, 2 defaults only)"
+	if summary.String() != expected {
+		t.Fatal("The summary should contain the number of resources which only differ in default values. Got: " + summary.String())
+	}
+}
+
+func TestDiffSummaryStringContainsReorderedResources(t *testing.T) {
+	summary := DiffSummary{Resources: 2, Modified: 1, Reordered: 1, LinesAdded: 2, LinesRemoved: 1}
+
+	expected := "2 resources changed (0 added, 0 removed, 1 modified, 0 renamed, 1 reordered), +2 -1"
+	if summary.String() != expected {
+		t.Fatal("The summary should contain the number of reordered resources. Got: " + summary.String())
+	}
+}
@@ -2,30 +2,90 @@ package kubernetes
 
 import "testing"
 
-func TestCalculateHashForServiceYieldsCorrectResult(t *testing.T) {
+func TestIDForServiceYieldsCorrectResult(t *testing.T) {
 	manifest := Manifest{
 		ApiVersion: "v1",
 		Kind:       "Service",
 		Name:       "backend",
 		Namespace:  "myapp-dev",
 	}
-	hash := manifest.CalculateHash()
+	id := manifest.ID()
 
-	if hash != "1716ec9caa244a38d55db16b3d88878c" {
-		t.Fatal("The hash for a Service manifest should be calculated correctly.")
+	if id != (ResourceID{Group: "", Version: "v1", Kind: "Service", Namespace: "myapp-dev", Name: "backend"}) {
+		t.Fatal("The resource identity for a Service manifest should be calculated correctly.")
 	}
 }
 
-func TestCalculateHashForDeploymentYieldsCorrectResult(t *testing.T) {
+func TestIDForDeploymentYieldsCorrectResult(t *testing.T) {
 	manifest := Manifest{
 		ApiVersion: "apps/v1",
 		Kind:       "Deployment",
 		Name:       "frontend",
 		Namespace:  "foo-bar-baz",
 	}
-	hash := manifest.CalculateHash()
+	id := manifest.ID()
 
-	if hash != "0ebb1202e9ab7b9cb44b317fb984d6bd" {
-		t.Fatal("The hash for a Deployment manifest should be calculated correctly.")
+	if id != (ResourceID{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "foo-bar-baz", Name: "frontend"}) {
+		t.Fatal("The resource identity for a Deployment manifest should be calculated correctly.")
+	}
+}
+
+func TestKeyIgnoresApiVersionBump(t *testing.T) {
+	oldManifest := Manifest{
+		ApiVersion: "autoscaling/v2beta2",
+		Kind:       "HorizontalPodAutoscaler",
+		Name:       "frontend",
+		Namespace:  "foo-bar-baz",
+	}
+	newManifest := Manifest{
+		ApiVersion: "autoscaling/v2",
+		Kind:       "HorizontalPodAutoscaler",
+		Name:       "frontend",
+		Namespace:  "foo-bar-baz",
+	}
+
+	if oldManifest.Key() != newManifest.Key() {
+		t.Fatal("The key of a manifest should not change if only the version of the apiVersion changes.")
+	}
+}
+
+func TestKeyDiffersForDifferentGroups(t *testing.T) {
+	oldManifest := Manifest{
+		ApiVersion: "extensions/v1beta1",
+		Kind:       "Ingress",
+		Name:       "frontend",
+	}
+	newManifest := Manifest{
+		ApiVersion: "networking.k8s.io/v1",
+		Kind:       "Ingress",
+		Name:       "frontend",
+	}
+
+	if oldManifest.Key() == newManifest.Key() {
+		t.Fatal("The key of a manifest should change if the group of the apiVersion changes.")
+	}
+}
+
+func TestResourceIDStringYieldsReadableIdentity(t *testing.T) {
+	namespaced := ResourceID{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "my-namespace", Name: "my-app"}
+	if namespaced.String() != "apps/v1 Deployment my-namespace/my-app" {
+		t.Fatal("The string of a namespaced resource identity should contain apiVersion, kind, namespace and name. Got: " + namespaced.String())
+	}
+
+	clusterScoped := ResourceID{Version: "v1", Kind: "Namespace", Name: "my-namespace"}
+	if clusterScoped.String() != "v1 Namespace my-namespace" {
+		t.Fatal("The string of a cluster-scoped resource identity should contain apiVersion, kind and name. Got: " + clusterScoped.String())
+	}
+}
+
+func TestResourceIDFilePathYieldsVirtualFilePath(t *testing.T) {
+	namespaced := ResourceID{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "my-ns", Name: "my-app"}
+	if namespaced.FilePath() != "apps_v1/Deployment/my-ns/my-app.yaml" {
+		t.Fatal("The file path of a namespaced resource should contain apiVersion, kind, namespace and name. Got: " + namespaced.FilePath())
+	}
+
+	clusterScoped := ResourceID{Version: "v1", Kind: "Namespace", Name: "my-ns"}
+	if clusterScoped.FilePath() != "v1/Namespace/my-ns.yaml" {
+		t.Fatal("The file path of a cluster-scoped resource should contain apiVersion, kind and name. Got: " + clusterScoped.FilePath())
 	}
 }